@@ -0,0 +1,241 @@
+// Package cgroup 提供cgroup v1/v2资源限制与使用量检测，用于容器化部署
+package cgroup
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// cgroup版本
+const (
+	Version1 = 1 // cgroup v1（含v1控制器与v2混合挂载）
+	Version2 = 2 // cgroup v2统一层级
+)
+
+// Info 当前进程所在cgroup的资源限制与使用量
+type Info struct {
+	Version     int          `json:"version"`      // cgroup版本: 1 或 2
+	Path        string       `json:"path"`         // 当前进程的cgroup路径 (v1为memory控制器路径)
+	Memory      *MemoryStats `json:"memory"`       // 内存控制器，不可用时为nil
+	CPU         *CPUStats    `json:"cpu"`          // CPU控制器，不可用时为nil
+	Pids        *PidsStats   `json:"pids"`         // 进程数控制器，不可用时为nil
+	LastUpdated time.Time    `json:"last_updated"` // 最后更新时间
+}
+
+// MemoryStats cgroup内存控制器统计
+type MemoryStats struct {
+	Limit        uint64  `json:"limit"`         // 内存上限 (bytes)，0表示不限制 (v2 memory.max / v1 memory.limit_in_bytes)
+	High         uint64  `json:"high"`          // 节流阈值 (bytes)，0表示不限制 (仅v2 memory.high)
+	Usage        uint64  `json:"usage"`         // 当前使用量 (bytes)，包含页缓存
+	InactiveFile uint64  `json:"inactive_file"` // 非活跃文件页缓存 (bytes)，内存紧张时优先回收
+	WorkingSet   uint64  `json:"working_set"`   // 工作集 = Usage - InactiveFile，与docker stats/kubelet一致
+	SwapLimit    uint64  `json:"swap_limit"`    // 交换空间上限 (bytes)，0表示不限制
+	SwapUsage    uint64  `json:"swap_usage"`    // 交换空间使用量 (bytes)
+	UsedPercent  float64 `json:"used_percent"`  // 工作集占上限的百分比，不限制时为0
+
+	// memory.events计数器 (v1仅提供MaxEvents与OOMKills)
+	LowEvents  uint64 `json:"low_events"`  // 低于memory.low保护时仍被回收的次数
+	HighEvents uint64 `json:"high_events"` // 超过memory.high被节流的次数
+	MaxEvents  uint64 `json:"max_events"`  // 触及上限的次数 (v1为memory.failcnt)
+	OOMEvents  uint64 `json:"oom_events"`  // 触发OOM的次数
+	OOMKills   uint64 `json:"oom_kills"`   // OOM杀死进程的次数
+}
+
+// CPUStats cgroup CPU控制器统计
+type CPUStats struct {
+	Quota         int64   `json:"quota"`          // 每周期可用CPU时间 (微秒)，-1表示不限制
+	Period        uint64  `json:"period"`         // 调度周期 (微秒)
+	LimitCores    float64 `json:"limit_cores"`    // 配额折算的CPU核数，不限制时为0
+	Weight        uint64  `json:"weight"`         // 调度权重 (1-10000)，v1由cpu.shares换算
+	EffectiveCPUs int     `json:"effective_cpus"` // cpuset允许使用的CPU数量，未知时为0
+	Usage         uint64  `json:"usage"`          // 累计CPU时间 (微秒)
+	UserUsage     uint64  `json:"user_usage"`     // 累计用户态CPU时间 (微秒)
+	SystemUsage   uint64  `json:"system_usage"`   // 累计内核态CPU时间 (微秒)
+	NrPeriods     uint64  `json:"nr_periods"`     // 经历的调度周期数
+	NrThrottled   uint64  `json:"nr_throttled"`   // 被限流的周期数
+	ThrottledTime uint64  `json:"throttled_time"` // 累计被限流时间 (微秒)
+}
+
+// PidsStats cgroup进程数控制器统计
+type PidsStats struct {
+	Limit       uint64  `json:"limit"`        // 最大进程/线程数，0表示不限制
+	Current     uint64  `json:"current"`      // 当前进程/线程数
+	UsedPercent float64 `json:"used_percent"` // 使用率百分比，不限制时为0
+}
+
+// ContainerView 以cgroup配额为基准的资源视图
+// 不受限的资源回退为主机数值，因此在容器外调用与主机视角一致
+type ContainerView struct {
+	InCgroupLimit bool `json:"in_cgroup_limit"` // 是否存在任何cgroup资源限制
+
+	CPULimit         float64 `json:"cpu_limit"`          // 可用CPU核数: 配额、cpuset与主机核数中的最小值
+	CPUUsage         float64 `json:"cpu_usage"`          // 相对于CPULimit的使用率百分比 (需两次采样)
+	CPUThrottled     float64 `json:"cpu_throttled"`      // 采样区间内被限流的周期百分比
+	CPUThrottledTime float64 `json:"cpu_throttled_time"` // 采样区间内每秒被限流的时间 (秒/秒)
+
+	MemoryLimit       uint64  `json:"memory_limit"`        // 有效内存上限 (bytes): cgroup上限与主机内存中的较小值
+	MemoryUsed        uint64  `json:"memory_used"`         // 已用内存 (bytes)，cgroup内为工作集
+	MemoryAvailable   uint64  `json:"memory_available"`    // 可用内存 (bytes)
+	MemoryUsedPercent float64 `json:"memory_used_percent"` // 内存使用率百分比
+
+	PidsLimit       uint64  `json:"pids_limit"`        // 最大进程数，0表示不限制
+	PidsCurrent     uint64  `json:"pids_current"`      // 当前进程数
+	PidsUsedPercent float64 `json:"pids_used_percent"` // 进程数使用率百分比
+
+	LastUpdated time.Time `json:"last_updated"` // 最后更新时间
+}
+
+var (
+	lastCPUStats *CPUStats
+	lastCPUTime  time.Time
+)
+
+// GetInfo 获取当前进程所在cgroup的资源限制与使用量
+func GetInfo() (*Info, error) {
+	info := &Info{
+		LastUpdated: time.Now(),
+	}
+
+	err := getPlatformCgroupInfo(info)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Memory != nil {
+		memory := info.Memory
+		if memory.Usage > memory.InactiveFile {
+			memory.WorkingSet = memory.Usage - memory.InactiveFile
+		}
+		if memory.Limit > 0 {
+			memory.UsedPercent = float64(memory.WorkingSet) / float64(memory.Limit) * 100
+		}
+	}
+
+	if info.CPU != nil && info.CPU.Quota > 0 && info.CPU.Period > 0 {
+		info.CPU.LimitCores = float64(info.CPU.Quota) / float64(info.CPU.Period)
+	}
+
+	if info.Pids != nil && info.Pids.Limit > 0 {
+		info.Pids.UsedPercent = float64(info.Pids.Current) / float64(info.Pids.Limit) * 100
+	}
+
+	return info, nil
+}
+
+// GetContainerView 获取以cgroup配额为基准的资源视图
+// CPU使用率基于与上一次调用之间的差值，首次调用时为0
+func GetContainerView() (*ContainerView, error) {
+	info, err := GetInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	view := &ContainerView{
+		CPULimit:    float64(runtime.NumCPU()),
+		LastUpdated: info.LastUpdated,
+	}
+
+	if cpu := info.CPU; cpu != nil {
+		if cpu.LimitCores > 0 && cpu.LimitCores < view.CPULimit {
+			view.CPULimit = cpu.LimitCores
+			view.InCgroupLimit = true
+		}
+		if cpu.EffectiveCPUs > 0 && float64(cpu.EffectiveCPUs) < view.CPULimit {
+			view.CPULimit = float64(cpu.EffectiveCPUs)
+			view.InCgroupLimit = true
+		}
+
+		if lastCPUStats != nil {
+			calculateCPURates(lastCPUStats, cpu, info.LastUpdated.Sub(lastCPUTime), view)
+		}
+		lastCPUStats = cpu
+		lastCPUTime = info.LastUpdated
+	}
+
+	hostTotal, hostAvailable, err := getPlatformHostMemory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host memory: %v", err)
+	}
+	view.MemoryLimit = hostTotal
+	view.MemoryUsed = hostTotal - hostAvailable
+
+	if memory := info.Memory; memory != nil {
+		if memory.Limit > 0 && memory.Limit < hostTotal {
+			view.MemoryLimit = memory.Limit
+			view.InCgroupLimit = true
+		}
+		// 根cgroup没有使用量文件，此时保留主机数值
+		if memory.Usage > 0 {
+			view.MemoryUsed = memory.WorkingSet
+		}
+	}
+	if view.MemoryLimit > view.MemoryUsed {
+		view.MemoryAvailable = view.MemoryLimit - view.MemoryUsed
+	}
+	if view.MemoryLimit > 0 {
+		view.MemoryUsedPercent = float64(view.MemoryUsed) / float64(view.MemoryLimit) * 100
+	}
+
+	if pids := info.Pids; pids != nil {
+		view.PidsLimit = pids.Limit
+		view.PidsCurrent = pids.Current
+		view.PidsUsedPercent = pids.UsedPercent
+		if pids.Limit > 0 {
+			view.InCgroupLimit = true
+		}
+	}
+
+	return view, nil
+}
+
+// ControllerDir 返回当前进程指定控制器（如memory、cpu）所在的cgroup目录，v2表示目录位于cgroup v2统一层级
+// 混合模式下控制器绑定在v1层级，优先返回v1目录
+func ControllerDir(controller string) (string, bool, error) {
+	return getPlatformControllerDir(controller)
+}
+
+// V2Mount 返回cgroup v2统一层级的挂载点
+func V2Mount() (string, error) {
+	return getPlatformV2Mount()
+}
+
+// V2Path 返回当前进程相对于cgroup v2挂载点的路径
+// 位于根cgroup或启用了cgroup命名空间的容器中时为"/"
+func V2Path() (string, error) {
+	return getPlatformV2Path()
+}
+
+// GetContainerViewWithDuration 获取容器视图，首次调用时等待duration采样以得到CPU使用率
+func GetContainerViewWithDuration(duration time.Duration) (*ContainerView, error) {
+	if lastCPUStats == nil {
+		if _, err := GetContainerView(); err != nil {
+			return nil, err
+		}
+		time.Sleep(duration)
+	}
+
+	return GetContainerView()
+}
+
+// calculateCPURates 根据两次采样计算相对于配额的CPU使用率与限流比例
+func calculateCPURates(last, current *CPUStats, elapsed time.Duration, view *ContainerView) {
+	elapsedUsec := float64(elapsed.Microseconds())
+	if elapsedUsec <= 0 {
+		return
+	}
+
+	if current.Usage >= last.Usage && view.CPULimit > 0 {
+		view.CPUUsage = float64(current.Usage-last.Usage) / elapsedUsec / view.CPULimit * 100
+		if view.CPUUsage > 100 {
+			view.CPUUsage = 100
+		}
+	}
+
+	if current.NrPeriods > last.NrPeriods && current.NrThrottled >= last.NrThrottled {
+		view.CPUThrottled = float64(current.NrThrottled-last.NrThrottled) / float64(current.NrPeriods-last.NrPeriods) * 100
+	}
+	if current.ThrottledTime >= last.ThrottledTime {
+		view.CPUThrottledTime = float64(current.ThrottledTime-last.ThrottledTime) / elapsedUsec
+	}
+}
@@ -0,0 +1,505 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// Linux procfs路径
+const (
+	procSelfCgroup  = "/proc/self/cgroup"
+	procMeminfoPath = "/proc/meminfo"
+)
+
+// v1中不小于该值的限制视为不限制（内核以按页对齐的LONG_MAX表示无限制）
+const v1UnlimitedThreshold = 1 << 62
+
+// cpuacct.stat的时钟滴答频率 (USER_HZ)，Linux上恒为100
+const userHZ = 100
+
+// cgroupMount 一个cgroup层级的挂载
+type cgroupMount struct {
+	mountPoint  string   // 挂载点
+	root        string   // 挂载的层级内根路径，容器中通常为容器自身的cgroup
+	version     int      // 1 或 2
+	controllers []string // v1挂载的控制器 (来自超级块选项)
+}
+
+// cgroupLayout 当前进程的cgroup层级布局
+type cgroupLayout struct {
+	mounts   []cgroupMount
+	v1Paths  map[string]string // v1控制器 -> 进程cgroup路径
+	v2Path   string            // v2统一层级中的进程cgroup路径
+	hasV2    bool              // /proc/self/cgroup中是否有v2条目
+	v2Mount  *cgroupMount      // v2挂载
+	v1Active bool              // 是否有资源控制器挂载在v1层级
+}
+
+// getPlatformCgroupInfo 获取平台cgroup信息
+func getPlatformCgroupInfo(info *Info) error {
+	layout, err := readCgroupLayout()
+	if err != nil {
+		return err
+	}
+
+	if layout.v1Active {
+		info.Version = Version1
+		info.Path = layout.v1Paths["memory"]
+	} else if layout.v2Mount != nil && layout.hasV2 {
+		info.Version = Version2
+		info.Path = layout.v2Path
+	} else {
+		return fmt.Errorf("no cgroup hierarchy mounted")
+	}
+
+	if dir, v2, ok := layout.controllerDir("memory"); ok {
+		if v2 {
+			info.Memory = readMemoryV2(dir)
+		} else {
+			info.Memory = readMemoryV1(dir)
+		}
+	}
+
+	if dir, v2, ok := layout.controllerDir("cpu"); ok {
+		if v2 {
+			info.CPU = readCPUV2(dir)
+		} else {
+			acctDir, _, _ := layout.controllerDir("cpuacct")
+			info.CPU = readCPUV1(dir, acctDir)
+		}
+	}
+	if info.CPU != nil {
+		if dir, v2, ok := layout.controllerDir("cpuset"); ok {
+			info.CPU.EffectiveCPUs = readEffectiveCPUs(dir, v2)
+		}
+	}
+
+	if dir, _, ok := layout.controllerDir("pids"); ok {
+		info.Pids = readPids(dir)
+	}
+
+	return nil
+}
+
+// getPlatformHostMemory 从/proc/meminfo获取主机总内存与可用内存 (bytes)
+func getPlatformHostMemory() (uint64, uint64, error) {
+	values, err := procfs.ReadKeyValueFile(procMeminfoPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	total := values["MemTotal:"] * 1024
+	available, ok := values["MemAvailable:"]
+	if !ok {
+		// 3.14之前的内核没有MemAvailable
+		available = values["MemFree:"] + values["Buffers:"] + values["Cached:"]
+	}
+	available *= 1024
+	if available > total {
+		available = total
+	}
+
+	return total, available, nil
+}
+
+// getPlatformControllerDir 获取当前进程指定控制器所在的cgroup目录
+func getPlatformControllerDir(controller string) (string, bool, error) {
+	layout, err := readCgroupLayout()
+	if err != nil {
+		return "", false, err
+	}
+
+	dir, v2, ok := layout.controllerDir(controller)
+	if !ok {
+		return "", false, fmt.Errorf("cgroup controller %s not available", controller)
+	}
+	return dir, v2, nil
+}
+
+// getPlatformV2Mount 获取cgroup v2挂载点
+func getPlatformV2Mount() (string, error) {
+	layout, err := readCgroupLayout()
+	if err != nil {
+		return "", err
+	}
+
+	if layout.v2Mount == nil {
+		return "", fmt.Errorf("cgroup v2 hierarchy not mounted")
+	}
+	return layout.v2Mount.mountPoint, nil
+}
+
+// getPlatformV2Path 获取当前进程相对于cgroup v2挂载点的路径
+func getPlatformV2Path() (string, error) {
+	layout, err := readCgroupLayout()
+	if err != nil {
+		return "", err
+	}
+
+	return layout.v2Relative()
+}
+
+// readCgroupLayout 解析/proc/self/cgroup与/proc/self/mountinfo
+func readCgroupLayout() (*cgroupLayout, error) {
+	data, err := os.ReadFile(procSelfCgroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", procSelfCgroup, err)
+	}
+
+	mounts, err := procfs.ReadMountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCgroupLayout(string(data), cgroupMounts(mounts)), nil
+}
+
+// parseCgroupLayout 根据/proc/self/cgroup内容与cgroup挂载构建布局
+// 格式: "hierarchy-ID:controller-list:cgroup-path"，v2条目为 "0::/path"
+func parseCgroupLayout(data string, mounts []cgroupMount) *cgroupLayout {
+	layout := &cgroupLayout{
+		mounts:  mounts,
+		v1Paths: make(map[string]string),
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			layout.v2Path = parts[2]
+			layout.hasV2 = true
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			layout.v1Paths[controller] = parts[2]
+		}
+	}
+
+	for i := range layout.mounts {
+		mount := &layout.mounts[i]
+		switch mount.version {
+		case Version2:
+			if layout.v2Mount == nil {
+				layout.v2Mount = mount
+			}
+		case Version1:
+			for _, controller := range []string{"memory", "cpu", "cpuacct", "cpuset", "pids"} {
+				if mount.hasController(controller) {
+					layout.v1Active = true
+				}
+			}
+		}
+	}
+
+	return layout
+}
+
+// controllerDir 返回控制器在当前进程cgroup中的目录，优先使用v1挂载（混合模式下控制器绑定在v1）
+func (l *cgroupLayout) controllerDir(controller string) (string, bool, bool) {
+	for i := range l.mounts {
+		mount := &l.mounts[i]
+		if mount.version != Version1 || !mount.hasController(controller) {
+			continue
+		}
+		path, ok := l.v1Paths[controller]
+		if !ok {
+			continue
+		}
+		return mount.resolve(path), false, true
+	}
+
+	if l.v2Mount == nil || !l.hasV2 {
+		return "", false, false
+	}
+
+	// 控制器需在v2根的cgroup.controllers中启用
+	data, err := os.ReadFile(filepath.Join(l.v2Mount.mountPoint, "cgroup.controllers"))
+	if err != nil {
+		return "", false, false
+	}
+	for _, enabled := range strings.Fields(string(data)) {
+		// cpuacct在v2中并入cpu控制器
+		if enabled == controller || (controller == "cpuacct" && enabled == "cpu") {
+			return l.v2Mount.resolve(l.v2Path), true, true
+		}
+	}
+
+	return "", false, false
+}
+
+// v2Relative 返回进程在v2统一层级中相对于挂载点的路径
+// 启用了cgroup命名空间的容器中/proc/self/cgroup为"0::/"，对应挂载点本身
+func (l *cgroupLayout) v2Relative() (string, error) {
+	if l.v2Mount == nil || !l.hasV2 {
+		return "", fmt.Errorf("process is not in a cgroup v2 hierarchy")
+	}
+	return l.v2Mount.relative(l.v2Path), nil
+}
+
+// hasController 检查v1挂载是否包含指定控制器
+func (m *cgroupMount) hasController(controller string) bool {
+	for _, c := range m.controllers {
+		if c == controller {
+			return true
+		}
+	}
+	return false
+}
+
+// relative 将进程cgroup路径映射为相对于挂载点的路径
+// 容器中挂载的根通常就是容器自身的cgroup（或启用了cgroup命名空间），需去掉根前缀；目录不存在时回退到挂载点
+func (m *cgroupMount) relative(path string) string {
+	relative := path
+	if m.root != "/" {
+		relative = strings.TrimPrefix(path, m.root)
+	}
+
+	relative = filepath.Clean("/" + relative)
+	if _, err := os.Stat(filepath.Join(m.mountPoint, relative)); err != nil {
+		return "/"
+	}
+	return relative
+}
+
+// resolve 将进程cgroup路径映射为文件系统目录
+func (m *cgroupMount) resolve(path string) string {
+	return filepath.Join(m.mountPoint, m.relative(path))
+}
+
+// cgroupMounts 从挂载记录中筛选cgroup挂载
+func cgroupMounts(mounts []procfs.MountInfo) []cgroupMount {
+	var result []cgroupMount
+	for _, mount := range mounts {
+		cgroup := cgroupMount{
+			root:       mount.Root,
+			mountPoint: mount.Mountpoint,
+		}
+		switch mount.FSType {
+		case "cgroup2":
+			cgroup.version = Version2
+		case "cgroup":
+			cgroup.version = Version1
+			cgroup.controllers = strings.Split(mount.SuperOptions, ",")
+		default:
+			continue
+		}
+		result = append(result, cgroup)
+	}
+	return result
+}
+
+// readMemoryV2 读取cgroup v2内存控制器
+func readMemoryV2(dir string) *MemoryStats {
+	stat, statErr := procfs.ReadKeyValueFile(filepath.Join(dir, "memory.stat"))
+	usage, usageErr := readCgroupUint(filepath.Join(dir, "memory.current"))
+	if statErr != nil && usageErr != nil {
+		return nil
+	}
+
+	memory := &MemoryStats{
+		Usage:        usage,
+		InactiveFile: stat["inactive_file"],
+	}
+	memory.Limit, _ = readCgroupUint(filepath.Join(dir, "memory.max"))
+	memory.High, _ = readCgroupUint(filepath.Join(dir, "memory.high"))
+	memory.SwapLimit, _ = readCgroupUint(filepath.Join(dir, "memory.swap.max"))
+	memory.SwapUsage, _ = readCgroupUint(filepath.Join(dir, "memory.swap.current"))
+
+	if events, err := procfs.ReadKeyValueFile(filepath.Join(dir, "memory.events")); err == nil {
+		memory.LowEvents = events["low"]
+		memory.HighEvents = events["high"]
+		memory.MaxEvents = events["max"]
+		memory.OOMEvents = events["oom"]
+		memory.OOMKills = events["oom_kill"]
+	}
+
+	return memory
+}
+
+// readMemoryV1 读取cgroup v1内存控制器
+func readMemoryV1(dir string) *MemoryStats {
+	usage, err := readCgroupUint(filepath.Join(dir, "memory.usage_in_bytes"))
+	if err != nil {
+		return nil
+	}
+
+	memory := &MemoryStats{
+		Usage: usage,
+	}
+	memory.Limit, _ = readCgroupUint(filepath.Join(dir, "memory.limit_in_bytes"))
+	memory.MaxEvents, _ = readCgroupUint(filepath.Join(dir, "memory.failcnt"))
+
+	// 层级统计(total_*)包含子cgroup
+	if stat, err := procfs.ReadKeyValueFile(filepath.Join(dir, "memory.stat")); err == nil {
+		if inactive, ok := stat["total_inactive_file"]; ok {
+			memory.InactiveFile = inactive
+		} else {
+			memory.InactiveFile = stat["inactive_file"]
+		}
+	}
+
+	// memsw为内存+交换空间之和，需要swapaccount=1
+	if memswLimit, err := readCgroupUint(filepath.Join(dir, "memory.memsw.limit_in_bytes")); err == nil {
+		if memswLimit > memory.Limit && memory.Limit > 0 {
+			memory.SwapLimit = memswLimit - memory.Limit
+		}
+		if memswUsage, err := readCgroupUint(filepath.Join(dir, "memory.memsw.usage_in_bytes")); err == nil && memswUsage > usage {
+			memory.SwapUsage = memswUsage - usage
+		}
+	}
+
+	// oom_kill计数自4.13内核提供
+	if control, err := procfs.ReadKeyValueFile(filepath.Join(dir, "memory.oom_control")); err == nil {
+		memory.OOMKills = control["oom_kill"]
+	}
+
+	return memory
+}
+
+// readCPUV2 读取cgroup v2 CPU控制器
+func readCPUV2(dir string) *CPUStats {
+	stat, err := procfs.ReadKeyValueFile(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return nil
+	}
+
+	cpu := &CPUStats{
+		Quota:         -1,
+		Usage:         stat["usage_usec"],
+		UserUsage:     stat["user_usec"],
+		SystemUsage:   stat["system_usec"],
+		NrPeriods:     stat["nr_periods"],
+		NrThrottled:   stat["nr_throttled"],
+		ThrottledTime: stat["throttled_usec"],
+	}
+
+	// cpu.max格式: "<quota|max> <period>"
+	if data, err := os.ReadFile(filepath.Join(dir, "cpu.max")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 {
+			if fields[0] != "max" {
+				cpu.Quota, _ = strconv.ParseInt(fields[0], 10, 64)
+			}
+			cpu.Period, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	cpu.Weight, _ = readCgroupUint(filepath.Join(dir, "cpu.weight"))
+
+	return cpu
+}
+
+// readCPUV1 读取cgroup v1 cpu与cpuacct控制器
+func readCPUV1(dir, acctDir string) *CPUStats {
+	cpu := &CPUStats{
+		Quota: -1,
+	}
+
+	found := false
+	if data, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_quota_us")); err == nil {
+		cpu.Quota, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		found = true
+	}
+	cpu.Period, _ = readCgroupUint(filepath.Join(dir, "cpu.cfs_period_us"))
+	if shares, err := readCgroupUint(filepath.Join(dir, "cpu.shares")); err == nil {
+		cpu.Weight = sharesToWeight(shares)
+	}
+
+	if stat, err := procfs.ReadKeyValueFile(filepath.Join(dir, "cpu.stat")); err == nil {
+		cpu.NrPeriods = stat["nr_periods"]
+		cpu.NrThrottled = stat["nr_throttled"]
+		cpu.ThrottledTime = stat["throttled_time"] / 1000
+		found = true
+	}
+
+	if acctDir != "" {
+		if usage, err := readCgroupUint(filepath.Join(acctDir, "cpuacct.usage")); err == nil {
+			cpu.Usage = usage / 1000
+			found = true
+		}
+		if stat, err := procfs.ReadKeyValueFile(filepath.Join(acctDir, "cpuacct.stat")); err == nil {
+			cpu.UserUsage = stat["user"] * (1000000 / userHZ)
+			cpu.SystemUsage = stat["system"] * (1000000 / userHZ)
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return cpu
+}
+
+// sharesToWeight 将v1的cpu.shares (2-262144) 换算为v2的cpu.weight (1-10000)，与systemd/runc一致
+func sharesToWeight(shares uint64) uint64 {
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// readEffectiveCPUs 读取cpuset允许的CPU数量
+func readEffectiveCPUs(dir string, v2 bool) int {
+	names := []string{"cpuset.effective_cpus", "cpuset.cpus"}
+	if v2 {
+		names = []string{"cpuset.cpus.effective"}
+	}
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if cpus, err := procfs.ParseCPUList(string(data)); err == nil && len(cpus) > 0 {
+			return len(cpus)
+		}
+	}
+	return 0
+}
+
+// readPids 读取pids控制器
+func readPids(dir string) *PidsStats {
+	current, err := readCgroupUint(filepath.Join(dir, "pids.current"))
+	if err != nil {
+		return nil
+	}
+
+	pids := &PidsStats{
+		Current: current,
+	}
+	pids.Limit, _ = readCgroupUint(filepath.Join(dir, "pids.max"))
+
+	return pids
+}
+
+// readCgroupUint 读取单值cgroup文件，"max"与v1中的超大值表示不限制，返回0
+func readCgroupUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	text := strings.TrimSpace(string(data))
+	if text == "max" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s: %q", path, text)
+	}
+	if value >= v1UnlimitedThreshold {
+		return 0, nil
+	}
+	return value, nil
+}
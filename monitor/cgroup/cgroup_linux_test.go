@@ -0,0 +1,48 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestV2Relative(t *testing.T) {
+	mountPoint := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(mountPoint, "user.slice", "app.service"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cgroup string
+		root   string
+		want   string
+	}{
+		// 启用了cgroup命名空间的容器，进程cgroup就是挂载点本身
+		{"namespaced", "0::/\n", "/", "/"},
+		{"host", "0::/user.slice/app.service\n", "/", "/user.slice/app.service"},
+		// 未启用cgroup命名空间、挂载根为容器自身cgroup
+		{"container mount root", "0::/docker/0123abcd\n", "/docker/0123abcd", "/"},
+		{"hybrid", "12:memory:/user.slice\n0::/user.slice/app.service\n", "/", "/user.slice/app.service"},
+		{"missing directory", "0::/gone.scope\n", "/", "/"},
+	}
+
+	for _, test := range tests {
+		layout := parseCgroupLayout(test.cgroup, []cgroupMount{{mountPoint: mountPoint, root: test.root, version: Version2}})
+		got, err := layout.v2Relative()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	layout := parseCgroupLayout("12:memory:/user.slice\n", []cgroupMount{{mountPoint: mountPoint, root: "/", version: Version2}})
+	if _, err := layout.v2Relative(); err == nil {
+		t.Error("expected error for process without a cgroup v2 entry")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package cgroup
+
+import (
+	"fmt"
+)
+
+// getPlatformCgroupInfo Windows没有cgroup
+func getPlatformCgroupInfo(info *Info) error {
+	return fmt.Errorf("cgroups not supported on Windows")
+}
+
+// getPlatformHostMemory Windows没有cgroup，容器视图不可用
+func getPlatformHostMemory() (uint64, uint64, error) {
+	return 0, 0, fmt.Errorf("cgroups not supported on Windows")
+}
+
+// getPlatformControllerDir Windows没有cgroup
+func getPlatformControllerDir(controller string) (string, bool, error) {
+	return "", false, fmt.Errorf("cgroups not supported on Windows")
+}
+
+// getPlatformV2Mount Windows没有cgroup
+func getPlatformV2Mount() (string, error) {
+	return "", fmt.Errorf("cgroups not supported on Windows")
+}
+
+// getPlatformV2Path Windows没有cgroup
+func getPlatformV2Path() (string, error) {
+	return "", fmt.Errorf("cgroups not supported on Windows")
+}
@@ -0,0 +1,368 @@
+package cpu
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// 本文件是ChaCha20-Poly1305 (RFC 8439) 与 XChaCha20-Poly1305 的纯Go实现，
+// 与golang.org/x/crypto的通用实现等价，仅用于加密算法吞吐量基准测试，
+// 以保持本模块零外部依赖；它没有x/crypto在amd64/arm64上的汇编优化，吞吐量偏低
+
+const (
+	chachaKeySize    = 32
+	chachaNonceSize  = 12
+	xchachaNonceSize = 24
+	poly1305TagSize  = 16
+	chachaBlockSize  = 64
+)
+
+// chacha20Poly1305 实现cipher.AEAD
+type chacha20Poly1305 struct {
+	key      [chachaKeySize]byte
+	extended bool // XChaCha20-Poly1305
+}
+
+// newChaCha20Poly1305 创建ChaCha20-Poly1305 AEAD
+func newChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if len(key) != chachaKeySize {
+		return nil, errors.New("chacha20poly1305: bad key length")
+	}
+	aead := &chacha20Poly1305{}
+	copy(aead.key[:], key)
+	return aead, nil
+}
+
+// newXChaCha20Poly1305 创建XChaCha20-Poly1305 AEAD（24字节随机数）
+func newXChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if len(key) != chachaKeySize {
+		return nil, errors.New("chacha20poly1305: bad key length")
+	}
+	aead := &chacha20Poly1305{extended: true}
+	copy(aead.key[:], key)
+	return aead, nil
+}
+
+func (c *chacha20Poly1305) NonceSize() int {
+	if c.extended {
+		return xchachaNonceSize
+	}
+	return chachaNonceSize
+}
+
+func (c *chacha20Poly1305) Overhead() int {
+	return poly1305TagSize
+}
+
+func (c *chacha20Poly1305) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != c.NonceSize() {
+		panic("chacha20poly1305: bad nonce length passed to Seal")
+	}
+
+	key, chachaNonce := c.subKeyAndNonce(nonce)
+	ret, out := sliceForAppend(dst, len(plaintext)+poly1305TagSize)
+
+	var polyKey [32]byte
+	var block [chachaBlockSize]byte
+	chachaBlock(&block, &key, 0, &chachaNonce)
+	copy(polyKey[:], block[:32])
+
+	chachaXOR(out[:len(plaintext)], plaintext, &key, 1, &chachaNonce)
+
+	tag := aeadTag(&polyKey, additionalData, out[:len(plaintext)])
+	copy(out[len(plaintext):], tag[:])
+
+	return ret
+}
+
+func (c *chacha20Poly1305) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != c.NonceSize() {
+		panic("chacha20poly1305: bad nonce length passed to Open")
+	}
+	if len(ciphertext) < poly1305TagSize {
+		return nil, errors.New("chacha20poly1305: message authentication failed")
+	}
+
+	key, chachaNonce := c.subKeyAndNonce(nonce)
+	body := ciphertext[:len(ciphertext)-poly1305TagSize]
+
+	var polyKey [32]byte
+	var block [chachaBlockSize]byte
+	chachaBlock(&block, &key, 0, &chachaNonce)
+	copy(polyKey[:], block[:32])
+
+	tag := aeadTag(&polyKey, additionalData, body)
+	if subtle.ConstantTimeCompare(tag[:], ciphertext[len(body):]) != 1 {
+		return nil, errors.New("chacha20poly1305: message authentication failed")
+	}
+
+	ret, out := sliceForAppend(dst, len(body))
+	chachaXOR(out, body, &key, 1, &chachaNonce)
+	return ret, nil
+}
+
+// subKeyAndNonce 计算实际使用的密钥与12字节随机数（XChaCha20先做HChaCha20派生）
+func (c *chacha20Poly1305) subKeyAndNonce(nonce []byte) ([chachaKeySize]byte, [chachaNonceSize]byte) {
+	var chachaNonce [chachaNonceSize]byte
+	if !c.extended {
+		copy(chachaNonce[:], nonce)
+		return c.key, chachaNonce
+	}
+
+	subKey := hChaCha20(&c.key, nonce[:16])
+	copy(chachaNonce[4:], nonce[16:])
+	return subKey, chachaNonce
+}
+
+// aeadTag 按RFC 8439 2.8计算Poly1305认证标签
+func aeadTag(polyKey *[32]byte, additionalData, ciphertext []byte) [poly1305TagSize]byte {
+	mac := newPoly1305(polyKey)
+	mac.write(additionalData)
+	mac.writePadding(len(additionalData))
+	mac.write(ciphertext)
+	mac.writePadding(len(ciphertext))
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[0:], uint64(len(additionalData)))
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(ciphertext)))
+	mac.write(lengths[:])
+
+	return mac.sum()
+}
+
+// sliceForAppend 扩展dst以容纳n字节，返回完整切片与新增部分
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
+
+// chachaQuarterRound ChaCha四分之一轮
+func chachaQuarterRound(a, b, c, d uint32) (uint32, uint32, uint32, uint32) {
+	a += b
+	d ^= a
+	d = bits.RotateLeft32(d, 16)
+	c += d
+	b ^= c
+	b = bits.RotateLeft32(b, 12)
+	a += b
+	d ^= a
+	d = bits.RotateLeft32(d, 8)
+	c += d
+	b ^= c
+	b = bits.RotateLeft32(b, 7)
+	return a, b, c, d
+}
+
+// chachaInitState 构造ChaCha20初始状态
+func chachaInitState(key *[chachaKeySize]byte, counter uint32, nonce *[chachaNonceSize]byte) [16]uint32 {
+	return [16]uint32{
+		0x61707865, 0x3320646e, 0x79622d32, 0x6b206574,
+		binary.LittleEndian.Uint32(key[0:]),
+		binary.LittleEndian.Uint32(key[4:]),
+		binary.LittleEndian.Uint32(key[8:]),
+		binary.LittleEndian.Uint32(key[12:]),
+		binary.LittleEndian.Uint32(key[16:]),
+		binary.LittleEndian.Uint32(key[20:]),
+		binary.LittleEndian.Uint32(key[24:]),
+		binary.LittleEndian.Uint32(key[28:]),
+		counter,
+		binary.LittleEndian.Uint32(nonce[0:]),
+		binary.LittleEndian.Uint32(nonce[4:]),
+		binary.LittleEndian.Uint32(nonce[8:]),
+	}
+}
+
+// chachaRounds 执行20轮（10次列轮+对角轮）
+func chachaRounds(x *[16]uint32) {
+	for i := 0; i < 10; i++ {
+		x[0], x[4], x[8], x[12] = chachaQuarterRound(x[0], x[4], x[8], x[12])
+		x[1], x[5], x[9], x[13] = chachaQuarterRound(x[1], x[5], x[9], x[13])
+		x[2], x[6], x[10], x[14] = chachaQuarterRound(x[2], x[6], x[10], x[14])
+		x[3], x[7], x[11], x[15] = chachaQuarterRound(x[3], x[7], x[11], x[15])
+
+		x[0], x[5], x[10], x[15] = chachaQuarterRound(x[0], x[5], x[10], x[15])
+		x[1], x[6], x[11], x[12] = chachaQuarterRound(x[1], x[6], x[11], x[12])
+		x[2], x[7], x[8], x[13] = chachaQuarterRound(x[2], x[7], x[8], x[13])
+		x[3], x[4], x[9], x[14] = chachaQuarterRound(x[3], x[4], x[9], x[14])
+	}
+}
+
+// chachaBlock 生成一个64字节的密钥流块
+func chachaBlock(out *[chachaBlockSize]byte, key *[chachaKeySize]byte, counter uint32, nonce *[chachaNonceSize]byte) {
+	state := chachaInitState(key, counter, nonce)
+	x := state
+	chachaRounds(&x)
+	for i := range x {
+		binary.LittleEndian.PutUint32(out[i*4:], x[i]+state[i])
+	}
+}
+
+// chachaXOR 用从counter开始的密钥流异或src写入dst
+func chachaXOR(dst, src []byte, key *[chachaKeySize]byte, counter uint32, nonce *[chachaNonceSize]byte) {
+	state := chachaInitState(key, counter, nonce)
+
+	// 完整块直接按字异或，避免中间缓冲
+	for len(src) >= chachaBlockSize {
+		x := state
+		chachaRounds(&x)
+		for i := range x {
+			word := binary.LittleEndian.Uint32(src[i*4:]) ^ (x[i] + state[i])
+			binary.LittleEndian.PutUint32(dst[i*4:], word)
+		}
+		state[12]++
+		dst, src = dst[chachaBlockSize:], src[chachaBlockSize:]
+	}
+
+	if len(src) > 0 {
+		var block [chachaBlockSize]byte
+		chachaBlock(&block, key, state[12], nonce)
+		subtle.XORBytes(dst, src, block[:len(src)])
+	}
+}
+
+// hChaCha20 XChaCha20的子密钥派生函数
+func hChaCha20(key *[chachaKeySize]byte, nonce []byte) [chachaKeySize]byte {
+	var n [chachaNonceSize]byte
+	copy(n[:], nonce[4:16])
+	x := chachaInitState(key, binary.LittleEndian.Uint32(nonce[0:4]), &n)
+	chachaRounds(&x)
+
+	var out [chachaKeySize]byte
+	for i, word := range []uint32{x[0], x[1], x[2], x[3], x[12], x[13], x[14], x[15]} {
+		binary.LittleEndian.PutUint32(out[i*4:], word)
+	}
+	return out
+}
+
+// poly1305 Poly1305消息认证码，使用3个64位分量表示130位累加器
+type poly1305 struct {
+	h      [3]uint64
+	r      [2]uint64
+	s      [2]uint64
+	buffer [poly1305TagSize]byte
+	offset int
+}
+
+// newPoly1305 使用一次性密钥创建Poly1305
+func newPoly1305(key *[32]byte) *poly1305 {
+	p := &poly1305{}
+	p.r[0] = binary.LittleEndian.Uint64(key[0:8]) & 0x0FFFFFFC0FFFFFFF
+	p.r[1] = binary.LittleEndian.Uint64(key[8:16]) & 0x0FFFFFFC0FFFFFFC
+	p.s[0] = binary.LittleEndian.Uint64(key[16:24])
+	p.s[1] = binary.LittleEndian.Uint64(key[24:32])
+	return p
+}
+
+// write 追加消息数据
+func (p *poly1305) write(data []byte) {
+	if p.offset > 0 {
+		n := copy(p.buffer[p.offset:], data)
+		p.offset += n
+		data = data[n:]
+		if p.offset < poly1305TagSize {
+			return
+		}
+		p.block(p.buffer[:], 1)
+		p.offset = 0
+	}
+
+	for len(data) >= poly1305TagSize {
+		p.block(data[:poly1305TagSize], 1)
+		data = data[poly1305TagSize:]
+	}
+
+	if len(data) > 0 {
+		p.offset = copy(p.buffer[:], data)
+	}
+}
+
+// writePadding 补零到16字节边界
+func (p *poly1305) writePadding(length int) {
+	if rem := length % poly1305TagSize; rem != 0 {
+		var zeros [poly1305TagSize]byte
+		p.write(zeros[:poly1305TagSize-rem])
+	}
+}
+
+// block 处理一个16字节块，hibit为2^128位（完整块为1）
+func (p *poly1305) block(m []byte, hibit uint64) {
+	h0, h1, h2 := p.h[0], p.h[1], p.h[2]
+	r0, r1 := p.r[0], p.r[1]
+
+	// h += m
+	var c uint64
+	h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(m[0:8]), 0)
+	h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(m[8:16]), c)
+	h2 += c + hibit
+
+	// h *= r
+	h0r0Hi, h0r0Lo := bits.Mul64(h0, r0)
+	h1r0Hi, h1r0Lo := bits.Mul64(h1, r0)
+	h0r1Hi, h0r1Lo := bits.Mul64(h0, r1)
+	h1r1Hi, h1r1Lo := bits.Mul64(h1, r1)
+	h2r0 := h2 * r0
+	h2r1 := h2 * r1
+
+	m1Lo, c := bits.Add64(h1r0Lo, h0r1Lo, 0)
+	m1Hi := h1r0Hi + h0r1Hi + c
+	m2Lo, c := bits.Add64(h2r0, h1r1Lo, 0)
+	m2Hi := h1r1Hi + c
+	m3 := h2r1
+
+	t0 := h0r0Lo
+	t1, c := bits.Add64(m1Lo, h0r0Hi, 0)
+	t2, c := bits.Add64(m2Lo, m1Hi, c)
+	t3 := m3 + m2Hi + c
+
+	// 模 2^130-5 约简: 高位部分 X*2^130 ≡ 5X = 4X + X
+	h0, h1, h2 = t0, t1, t2&3
+	h0, c = bits.Add64(h0, t2&^3, 0)
+	h1, c = bits.Add64(h1, t3, c)
+	h2 += c
+
+	h0, c = bits.Add64(h0, (t2>>2)|(t3<<62), 0)
+	h1, c = bits.Add64(h1, t3>>2, c)
+	h2 += c
+
+	p.h[0], p.h[1], p.h[2] = h0, h1, h2
+}
+
+// sum 计算最终认证标签
+func (p *poly1305) sum() [poly1305TagSize]byte {
+	if p.offset > 0 {
+		var last [poly1305TagSize]byte
+		copy(last[:], p.buffer[:p.offset])
+		last[p.offset] = 1
+		p.block(last[:], 0)
+		p.offset = 0
+	}
+
+	h0, h1, h2 := p.h[0], p.h[1], p.h[2]
+
+	// 若 h >= 2^130-5 则减去模数（常数时间选择）
+	t0, b := bits.Sub64(h0, 0xFFFFFFFFFFFFFFFB, 0)
+	t1, b := bits.Sub64(h1, 0xFFFFFFFFFFFFFFFF, b)
+	_, b = bits.Sub64(h2, 3, b)
+	mask := b - 1
+	h0 = (h0 &^ mask) | (t0 & mask)
+	h1 = (h1 &^ mask) | (t1 & mask)
+
+	// tag = (h + s) mod 2^128
+	var c uint64
+	h0, c = bits.Add64(h0, p.s[0], 0)
+	h1, _ = bits.Add64(h1, p.s[1], c)
+
+	var tag [poly1305TagSize]byte
+	binary.LittleEndian.PutUint64(tag[0:8], h0)
+	binary.LittleEndian.PutUint64(tag[8:16], h1)
+	return tag
+}
@@ -0,0 +1,137 @@
+package cpu
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// sunscreen RFC 8439 测试向量使用的明文
+const sunscreen = "Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it."
+
+// decodeHex 解码带空格或冒号分隔的十六进制字符串
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	s = strings.NewReplacer(" ", "", ":", "", "\n", "", "\t", "").Replace(s)
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+// sequentialKey 返回从start开始递增的32字节密钥
+func sequentialKey(start byte) [chachaKeySize]byte {
+	var key [chachaKeySize]byte
+	for i := range key {
+		key[i] = start + byte(i)
+	}
+	return key
+}
+
+// RFC 8439 2.3.2
+func TestChaCha20Block(t *testing.T) {
+	key := sequentialKey(0)
+	var nonce [chachaNonceSize]byte
+	copy(nonce[:], decodeHex(t, "00 00 00 09 00 00 00 4a 00 00 00 00"))
+
+	var block [chachaBlockSize]byte
+	chachaBlock(&block, &key, 1, &nonce)
+
+	want := decodeHex(t, `
+		10 f1 e7 e4 d1 3b 59 15 50 0f dd 1f a3 20 71 c4
+		c7 d1 f4 c7 33 c0 68 03 04 22 aa 9a c3 d4 6c 4e
+		d2 82 64 46 07 9f aa 09 14 c2 d7 05 d9 8b 02 a2
+		b5 12 9c d1 de 16 4e b9 cb d0 83 e8 a2 50 3c 4e`)
+	if !bytes.Equal(block[:], want) {
+		t.Errorf("block = %x, want %x", block, want)
+	}
+}
+
+// RFC 8439 2.4.2
+func TestChaCha20Encryption(t *testing.T) {
+	key := sequentialKey(0)
+	var nonce [chachaNonceSize]byte
+	copy(nonce[:], decodeHex(t, "00 00 00 00 00 00 00 4a 00 00 00 00"))
+
+	out := make([]byte, len(sunscreen))
+	chachaXOR(out, []byte(sunscreen), &key, 1, &nonce)
+
+	want := decodeHex(t, `
+		6e 2e 35 9a 25 68 f9 80 41 ba 07 28 dd 0d 69 81
+		e9 7e 7a ec 1d 43 60 c2 0a 27 af cc fd 9f ae 0b
+		f9 1b 65 c5 52 47 33 ab 8f 59 3d ab cd 62 b3 57
+		16 39 d6 24 e6 51 52 ab 8f 53 0c 35 9f 08 61 d8
+		07 ca 0d bf 50 0d 6a 61 56 a3 8e 08 8a 22 b6 5e
+		52 bc 51 4d 16 cc f8 06 81 8c e9 1a b7 79 37 36
+		5a f9 0b bf 74 a3 5b e6 b4 0b 8e ed f2 78 5e 42
+		87 4d`)
+	if !bytes.Equal(out, want) {
+		t.Errorf("ciphertext = %x, want %x", out, want)
+	}
+}
+
+// RFC 8439 2.5.2
+func TestPoly1305(t *testing.T) {
+	var key [32]byte
+	copy(key[:], decodeHex(t, "85:d6:be:78:57:55:6d:33:7f:44:52:fe:42:d5:06:a8:01:03:80:8a:fb:0d:b2:fd:4a:bf:f6:af:41:49:f5:1b"))
+
+	mac := newPoly1305(&key)
+	mac.write([]byte("Cryptographic Forum Research Group"))
+	tag := mac.sum()
+
+	want := decodeHex(t, "a8:06:1d:c1:30:51:36:c6:c2:2b:8b:af:0c:01:27:a9")
+	if !bytes.Equal(tag[:], want) {
+		t.Errorf("tag = %x, want %x", tag, want)
+	}
+}
+
+// RFC 8439 2.8.2
+func TestChaCha20Poly1305AEAD(t *testing.T) {
+	key := sequentialKey(0x80)
+	nonce := decodeHex(t, "07 00 00 00 40 41 42 43 44 45 46 47")
+	additionalData := decodeHex(t, "50 51 52 53 c0 c1 c2 c3 c4 c5 c6 c7")
+
+	aead, err := newChaCha20Poly1305(key[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := aead.Seal(nil, nonce, []byte(sunscreen), additionalData)
+
+	want := decodeHex(t, `
+		d3 1a 8d 34 64 8e 60 db 7b 86 af bc 53 ef 7e c2
+		a4 ad ed 51 29 6e 08 fe a9 e2 b5 a7 36 ee 62 d6
+		3d be a4 5e 8c a9 67 12 82 fa fb 69 da 92 72 8b
+		1a 71 de 0a 9e 06 0b 29 05 d6 a5 b6 7e cd 3b 36
+		92 dd bd 7f 2d 77 8b 8c 98 03 ae e3 28 09 1b 58
+		fa b3 24 e4 fa d6 75 94 55 85 80 8b 48 31 d7 bc
+		3f f4 de f0 8e 4b 7a 9d e5 76 d2 65 86 ce c6 4b
+		61 16
+		1a e1 0b 59 4f 09 e2 6a 7e 90 2e cb d0 60 06 91`)
+	if !bytes.Equal(sealed, want) {
+		t.Fatalf("sealed = %x, want %x", sealed, want)
+	}
+
+	opened, err := aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil || string(opened) != sunscreen {
+		t.Errorf("Open = %q, %v", opened, err)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := aead.Open(nil, nonce, sealed, additionalData); err == nil {
+		t.Error("Open accepted a tampered tag")
+	}
+}
+
+// draft-irtf-cfrg-xchacha 2.2.1
+func TestHChaCha20(t *testing.T) {
+	key := sequentialKey(0)
+	nonce := decodeHex(t, "00 00 00 09 00 00 00 4a 00 00 00 00 31 41 59 27")
+
+	subKey := hChaCha20(&key, nonce)
+	want := decodeHex(t, "82413b42 27b27bfe d30e4250 8a877d73 a0f9e4d5 8a74a853 c12ec413 26d3ecdc")
+	if !bytes.Equal(subKey[:], want) {
+		t.Errorf("subkey = %x, want %x", subKey, want)
+	}
+}
@@ -0,0 +1,186 @@
+package cpu
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// 基准测试的加密算法名称（与sing-box的method命名一致）
+const (
+	CipherAES128GCM         = "aes-128-gcm"
+	CipherAES256GCM         = "aes-256-gcm"
+	CipherChaCha20Poly1305  = "chacha20-ietf-poly1305"
+	CipherXChaCha20Poly1305 = "xchacha20-ietf-poly1305"
+)
+
+// cipherBenchmarkBufferSize 每次加密的数据块大小，接近代理单次读写的典型长度
+const cipherBenchmarkBufferSize = 16 * 1024
+
+// CipherBenchmark 单个加密算法的吞吐量
+type CipherBenchmark struct {
+	Cipher     string  `json:"cipher"`      // 算法名称
+	PerCore    float64 `json:"per_core"`    // 单核吞吐量 (MB/s)
+	AllCores   float64 `json:"all_cores"`   // 全部核心并行吞吐量 (MB/s)
+	Goroutines int     `json:"goroutines"`  // 并行测试使用的goroutine数
+	BufferSize int     `json:"buffer_size"` // 每次加密的数据块大小 (bytes)
+}
+
+// CipherBenchmarkResult 加密算法基准测试结果
+type CipherBenchmarkResult struct {
+	Results []CipherBenchmark `json:"results"` // 各算法结果
+
+	// Fastest 单核吞吐量最高的算法
+	// 注意结果偏向AES-GCM: ChaCha20系列使用本模块的纯Go通用实现，而AES-GCM使用标准库的汇编实现；
+	// sing-box实际使用golang.org/x/crypto中有amd64/arm64汇编优化的ChaCha20-Poly1305，真实吞吐量明显更高。
+	// AES-GCM胜出且差距不大时，不代表sing-box中AES-GCM一定更快
+	Fastest string `json:"fastest"`
+
+	Duration    time.Duration `json:"duration"`     // 每项测试的持续时间
+	LastUpdated time.Time     `json:"last_updated"` // 最后更新时间
+}
+
+var (
+	cachedCipherBenchmark *CipherBenchmarkResult
+)
+
+// BenchmarkCiphers 测量本机AEAD算法吞吐量（AES-GCM、ChaCha20-Poly1305、XChaCha20-Poly1305）
+// 每个算法分别进行单核与全核测试，总耗时约为 8 * duration
+// ChaCha20系列为纯Go实现，吞吐量低于sing-box使用的汇编实现，见CipherBenchmarkResult.Fastest
+// 结果会被缓存，并随GetInfo返回的CPUInfo一起提供
+func BenchmarkCiphers(duration time.Duration) (*CipherBenchmarkResult, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid benchmark duration: %v", duration)
+	}
+
+	ciphers := []struct {
+		name    string
+		keySize int
+		create  func(key []byte) (cipher.AEAD, error)
+	}{
+		{CipherAES128GCM, 16, newAESGCM},
+		{CipherAES256GCM, 32, newAESGCM},
+		{CipherChaCha20Poly1305, chachaKeySize, newChaCha20Poly1305},
+		{CipherXChaCha20Poly1305, chachaKeySize, newXChaCha20Poly1305},
+	}
+
+	result := &CipherBenchmarkResult{
+		Duration: duration,
+	}
+
+	goroutines := runtime.NumCPU()
+	var fastest float64
+	for _, c := range ciphers {
+		key := make([]byte, c.keySize)
+		for i := range key {
+			key[i] = byte(i)
+		}
+
+		perCore, err := measureCipherThroughput(c.create, key, 1, duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to benchmark %s: %v", c.name, err)
+		}
+		allCores, err := measureCipherThroughput(c.create, key, goroutines, duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to benchmark %s: %v", c.name, err)
+		}
+
+		result.Results = append(result.Results, CipherBenchmark{
+			Cipher:     c.name,
+			PerCore:    perCore,
+			AllCores:   allCores,
+			Goroutines: goroutines,
+			BufferSize: cipherBenchmarkBufferSize,
+		})
+
+		if perCore > fastest {
+			fastest = perCore
+			result.Fastest = c.name
+		}
+	}
+
+	result.LastUpdated = time.Now()
+
+	// 缓存结果，并附加到已缓存的CPU信息
+	cachedCipherBenchmark = result
+	if cachedCPUInfo != nil {
+		cachedCPUInfo.CipherBenchmark = result
+	}
+
+	return result, nil
+}
+
+// GetCachedCipherBenchmark 获取最近一次基准测试结果，未测试时返回nil
+func GetCachedCipherBenchmark() *CipherBenchmarkResult {
+	return cachedCipherBenchmark
+}
+
+// Get 获取指定算法的结果
+func (r *CipherBenchmarkResult) Get(name string) (CipherBenchmark, bool) {
+	for _, result := range r.Results {
+		if result.Cipher == name {
+			return result, true
+		}
+	}
+	return CipherBenchmark{}, false
+}
+
+// newAESGCM 使用标准库创建AES-GCM
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// measureCipherThroughput 使用指定数量的goroutine持续加密，返回总吞吐量 (MB/s)
+func measureCipherThroughput(create func(key []byte) (cipher.AEAD, error), key []byte, goroutines int, duration time.Duration) (float64, error) {
+	aeads := make([]cipher.AEAD, goroutines)
+	for i := range aeads {
+		aead, err := create(key)
+		if err != nil {
+			return 0, err
+		}
+		aeads[i] = aead
+	}
+
+	var wg sync.WaitGroup
+	processed := make([]uint64, goroutines)
+	start := time.Now()
+	deadline := start.Add(duration)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+
+			aead := aeads[index]
+			nonce := make([]byte, aead.NonceSize())
+			plaintext := make([]byte, cipherBenchmarkBufferSize)
+			dst := make([]byte, 0, cipherBenchmarkBufferSize+aead.Overhead())
+
+			for time.Now().Before(deadline) {
+				// 每批加密若干次再检查时间，减少time.Now的开销
+				for j := 0; j < 16; j++ {
+					nonce[0]++
+					aead.Seal(dst[:0], nonce, plaintext, nil)
+				}
+				processed[index] += 16 * cipherBenchmarkBufferSize
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start).Seconds()
+
+	var total uint64
+	for _, bytes := range processed {
+		total += bytes
+	}
+
+	return float64(total) / elapsed / (1024 * 1024), nil
+}
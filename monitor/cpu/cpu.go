@@ -5,65 +5,114 @@ import (
 	"fmt"
 	"runtime"
 	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
 )
 
 // CPUInfo CPU基本信息
 type CPUInfo struct {
 	Model            string    `json:"model"`             // CPU型号
-	Cores            int       `json:"cores"`             // 总核心数
-	PerformanceCores int       `json:"performance_cores"` // 性能核心数（Apple Silicon）
-	EfficiencyCores  int       `json:"efficiency_cores"`  // 效率核心数（Apple Silicon）
+	Cores            int       `json:"cores"`             // 物理核心数
+	PerformanceCores int       `json:"performance_cores"` // 性能核心数（Apple Silicon、Intel混合架构、ARM big.LITTLE）
+	EfficiencyCores  int       `json:"efficiency_cores"`  // 效率核心数（Apple Silicon、Intel混合架构、ARM big.LITTLE）
 	Threads          int       `json:"threads"`           // 线程数
 	Frequency        float64   `json:"frequency"`         // 基础频率 (GHz)
 	MaxFrequency     float64   `json:"max_frequency"`     // 最大频率 (GHz)
 	Architecture     string    `json:"architecture"`      // 架构 (arm64, x86_64)
 	Vendor           string    `json:"vendor"`            // 厂商
 	Family           string    `json:"family"`            // CPU系列
-	CacheL1          int       `json:"cache_l1"`          // L1缓存大小 (KB)
+	CacheL1          int       `json:"cache_l1"`          // L1缓存大小 (KB，L1d+L1i)
+	CacheL1D         int       `json:"cache_l1d"`         // L1数据缓存大小 (KB)
+	CacheL1I         int       `json:"cache_l1i"`         // L1指令缓存大小 (KB)
 	CacheL2          int       `json:"cache_l2"`          // L2缓存大小 (KB)
 	CacheL3          int       `json:"cache_l3"`          // L3缓存大小 (KB)
 	Temperature      float64   `json:"temperature"`       // 温度 (℃)
 	LastUpdated      time.Time `json:"last_updated"`      // 最后更新时间
+
+	CipherBenchmark *CipherBenchmarkResult `json:"cipher_benchmark,omitempty"` // 最近一次加密算法基准测试结果
 }
 
 // CPUUsage CPU使用率信息
 type CPUUsage struct {
-	Overall          float64   `json:"overall"`           // 总体使用率
-	PerformanceCores float64   `json:"performance_cores"` // 性能核心使用率
-	EfficiencyCores  float64   `json:"efficiency_cores"`  // 效率核心使用率
-	PerCoreUsage     []float64 `json:"per_core_usage"`    // 每个核心使用率
-	User             float64   `json:"user"`              // 用户态使用率
-	System           float64   `json:"system"`            // 系统态使用率
-	Idle             float64   `json:"idle"`              // 空闲率
-	Nice             float64   `json:"nice"`              // Nice进程使用率
-	IOWait           float64   `json:"iowait"`            // IO等待时间
-	IRQ              float64   `json:"irq"`               // 硬中断时间
-	SoftIRQ          float64   `json:"soft_irq"`          // 软中断时间
-	LoadAvg1         float64   `json:"load_avg_1"`        // 1分钟负载平均
-	LoadAvg5         float64   `json:"load_avg_5"`        // 5分钟负载平均
-	LoadAvg15        float64   `json:"load_avg_15"`       // 15分钟负载平均
-	LastUpdated      time.Time `json:"last_updated"`      // 最后更新时间
+	Overall           float64   `json:"overall"`             // 总体使用率
+	PerformanceCores  float64   `json:"performance_cores"`   // 性能核心使用率
+	EfficiencyCores   float64   `json:"efficiency_cores"`    // 效率核心使用率
+	PerCoreUsage      []float64 `json:"per_core_usage"`      // 每个核心使用率
+	User              float64   `json:"user"`                // 用户态使用率
+	System            float64   `json:"system"`              // 系统态使用率
+	Idle              float64   `json:"idle"`                // 空闲率
+	Nice              float64   `json:"nice"`                // Nice进程使用率
+	IOWait            float64   `json:"iowait"`              // IO等待时间
+	IRQ               float64   `json:"irq"`                 // 硬中断时间
+	SoftIRQ           float64   `json:"soft_irq"`            // 软中断时间
+	Steal             float64   `json:"steal"`               // 被虚拟化宿主机窃取的时间
+	Guest             float64   `json:"guest"`               // 运行虚拟机客户系统的时间
+	LoadAvg1          float64   `json:"load_avg_1"`          // 1分钟负载平均
+	LoadAvg5          float64   `json:"load_avg_5"`          // 5分钟负载平均
+	LoadAvg15         float64   `json:"load_avg_15"`         // 15分钟负载平均
+	LoadPerCPU1       float64   `json:"load_per_cpu_1"`      // 1分钟负载 / 在线逻辑CPU数
+	LoadPerCPU5       float64   `json:"load_per_cpu_5"`      // 5分钟负载 / 在线逻辑CPU数
+	LoadPerCPU15      float64   `json:"load_per_cpu_15"`     // 15分钟负载 / 在线逻辑CPU数
+	ProcsRunning      uint64    `json:"procs_running"`       // 运行队列中的任务数
+	ProcsBlocked      uint64    `json:"procs_blocked"`       // 因I/O阻塞的任务数
+	TotalTasks        uint64    `json:"total_tasks"`         // 系统中的任务（线程）总数
+	ContextSwitchRate float64   `json:"context_switch_rate"` // 上下文切换速率 (次/秒)
+	ForkRate          float64   `json:"fork_rate"`           // 进程创建速率 (次/秒)
+	LastUpdated       time.Time `json:"last_updated"`        // 最后更新时间
 }
 
 // CPUStats CPU统计信息（用于计算使用率）
 type CPUStats struct {
-	User    uint64
-	Nice    uint64
-	System  uint64
-	Idle    uint64
-	IOWait  uint64
-	IRQ     uint64
-	SoftIRQ uint64
-	Steal   uint64
-	Guest   uint64
-	Total   uint64
+	User      uint64
+	Nice      uint64
+	System    uint64
+	Idle      uint64
+	IOWait    uint64
+	IRQ       uint64
+	SoftIRQ   uint64
+	Steal     uint64
+	Guest     uint64
+	GuestNice uint64
+	Total     uint64
+
+	ContextSwitches uint64 // 累计上下文切换次数
+	Forks           uint64 // 累计创建的进程数
+	ProcsRunning    uint64 // 当前可运行任务数
+	ProcsBlocked    uint64 // 当前阻塞任务数
 }
 
+// CoreFrequency 单个逻辑CPU的频率与调频策略
+type CoreFrequency struct {
+	CPU                int      `json:"cpu"`                 // 逻辑CPU编号
+	Current            float64  `json:"current"`             // 当前频率 (GHz)
+	Min                float64  `json:"min"`                 // 调频下限 (GHz)
+	Max                float64  `json:"max"`                 // 调频上限 (GHz)
+	HardwareMin        float64  `json:"hardware_min"`        // 硬件最低频率 (GHz)
+	HardwareMax        float64  `json:"hardware_max"`        // 硬件最高频率 (GHz)
+	Governor           string   `json:"governor"`            // 当前调频策略 (performance, powersave...)
+	AvailableGovernors []string `json:"available_governors"` // 可用调频策略
+	Driver             string   `json:"driver"`              // 调频驱动 (intel_pstate, acpi-cpufreq...)
+}
+
+// FrequencyInfo CPU频率详细信息
+type FrequencyInfo struct {
+	Status      string          `json:"status"`       // 状态: supported, unsupported
+	Cores       []CoreFrequency `json:"cores"`        // 每个逻辑CPU的频率信息
+	LastUpdated time.Time       `json:"last_updated"` // 最后更新时间
+}
+
+// 频率信息状态
+const (
+	FrequencyStatusSupported   = "supported"   // 支持调频接口
+	FrequencyStatusUnsupported = "unsupported" // 无调频接口（常见于虚拟机）
+)
+
 var (
-	lastCPUStats    *CPUStats
-	lastUpdateTime  time.Time
-	cachedCPUInfo   *CPUInfo
-	cacheExpireTime time.Time
+	lastCPUStats     *CPUStats
+	lastUpdateTime   time.Time
+	lastPerCoreStats []*CPUStats
+	cachedCPUInfo    *CPUInfo
+	cacheExpireTime  time.Time
 )
 
 // GetInfo 获取CPU基本信息（带缓存）
@@ -87,6 +136,9 @@ func GetInfo() (*CPUInfo, error) {
 		return nil, err
 	}
 
+	// 附加已有的加密算法基准测试结果
+	info.CipherBenchmark = cachedCipherBenchmark
+
 	// 缓存结果
 	cachedCPUInfo = info
 	cacheExpireTime = time.Now().Add(10 * time.Minute)
@@ -102,55 +154,81 @@ func GetUsage() (*CPUUsage, error) {
 // GetUsageWithDuration 获取指定采样时间的CPU使用率
 func GetUsageWithDuration(duration time.Duration) (*CPUUsage, error) {
 	// 获取当前CPU统计
-	currentStats, err := getCPUStats()
+	currentStats, currentPerCore, err := getCPUStats()
 	if err != nil {
 		return nil, err
 	}
+	currentTime := time.Now()
 
-	// 如果是第一次调用，等待一个采样周期
+	// 如果是第一次调用，用同一次采样建立汇总与每核心基线，只等待一个采样周期
 	if lastCPUStats == nil {
 		lastCPUStats = currentStats
-		lastUpdateTime = time.Now()
+		lastPerCoreStats = currentPerCore
+		lastUpdateTime = currentTime
 		time.Sleep(duration)
 
-		currentStats, err = getCPUStats()
+		currentStats, _, err = getCPUStats()
 		if err != nil {
 			return nil, err
 		}
+		currentTime = time.Now()
 	}
 
 	// 计算使用率
 	usage := calculateCPUUsage(lastCPUStats, currentStats)
-	usage.LastUpdated = time.Now()
+	usage.LastUpdated = currentTime
+
+	// 运行队列与进程计数器速率
+	usage.ProcsRunning = currentStats.ProcsRunning
+	usage.ProcsBlocked = currentStats.ProcsBlocked
+	if elapsed := currentTime.Sub(lastUpdateTime).Seconds(); elapsed > 0 {
+		usage.ContextSwitchRate = float64(procfs.CounterDelta(lastCPUStats.ContextSwitches, currentStats.ContextSwitches)) / elapsed
+		usage.ForkRate = float64(procfs.CounterDelta(lastCPUStats.Forks, currentStats.Forks)) / elapsed
+	}
 
 	// 获取每个核心的使用率（如果支持）
 	if perCoreUsage, err := getPerCoreCPUUsage(duration); err == nil {
 		usage.PerCoreUsage = perCoreUsage
+
+		// 混合架构下按性能核心/效率核心分别汇总
+		if performance, efficiency := getPlatformCoreClasses(); len(performance) > 0 && len(efficiency) > 0 {
+			usage.PerformanceCores = averageCoreUsage(perCoreUsage, performance)
+			usage.EfficiencyCores = averageCoreUsage(perCoreUsage, efficiency)
+		}
 	}
 
+	// 获取负载平均值
+	getPlatformLoadInfo(usage)
+
 	// 更新缓存
 	lastCPUStats = currentStats
-	lastUpdateTime = time.Now()
+	lastUpdateTime = currentTime
 
 	return usage, nil
 }
 
 // calculateCPUUsage 计算CPU使用率
 func calculateCPUUsage(last, current *CPUStats) *CPUUsage {
-	// 计算时间差
-	totalDiff := current.Total - last.Total
+	// 计算时间差（计数器回绕或重置时视为无数据）
+	totalDiff := procfs.CounterDelta(last.Total, current.Total)
 	if totalDiff == 0 {
 		return &CPUUsage{}
 	}
 
+	percent := func(last, current uint64) float64 {
+		return float64(procfs.CounterDelta(last, current)) / float64(totalDiff) * 100
+	}
+
 	usage := &CPUUsage{
-		User:    float64(current.User-last.User) / float64(totalDiff) * 100,
-		Nice:    float64(current.Nice-last.Nice) / float64(totalDiff) * 100,
-		System:  float64(current.System-last.System) / float64(totalDiff) * 100,
-		Idle:    float64(current.Idle-last.Idle) / float64(totalDiff) * 100,
-		IOWait:  float64(current.IOWait-last.IOWait) / float64(totalDiff) * 100,
-		IRQ:     float64(current.IRQ-last.IRQ) / float64(totalDiff) * 100,
-		SoftIRQ: float64(current.SoftIRQ-last.SoftIRQ) / float64(totalDiff) * 100,
+		User:    percent(last.User, current.User),
+		Nice:    percent(last.Nice, current.Nice),
+		System:  percent(last.System, current.System),
+		Idle:    percent(last.Idle, current.Idle),
+		IOWait:  percent(last.IOWait, current.IOWait),
+		IRQ:     percent(last.IRQ, current.IRQ),
+		SoftIRQ: percent(last.SoftIRQ, current.SoftIRQ),
+		Steal:   percent(last.Steal, current.Steal),
+		Guest:   percent(last.Guest+last.GuestNice, current.Guest+current.GuestNice),
 	}
 
 	// 计算总体使用率
@@ -159,6 +237,22 @@ func calculateCPUUsage(last, current *CPUStats) *CPUUsage {
 	return usage
 }
 
+// averageCoreUsage 计算指定逻辑CPU的平均使用率
+func averageCoreUsage(perCoreUsage []float64, cpus []int) float64 {
+	var sum float64
+	var count int
+	for _, cpu := range cpus {
+		if cpu >= 0 && cpu < len(perCoreUsage) {
+			sum += perCoreUsage[cpu]
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
 // GetTemperature 获取CPU温度（如果支持）
 func GetTemperature() (float64, error) {
 	return getPlatformCPUTemperature()
@@ -169,6 +263,31 @@ func GetFrequency() (float64, error) {
 	return getPlatformCPUFrequency()
 }
 
+// GetFrequencyDetails 获取每个核心的频率、调频范围与调频策略
+// 在没有调频接口的平台（如多数虚拟机）上返回Status为unsupported的结果而非错误
+func GetFrequencyDetails() (*FrequencyInfo, error) {
+	info := &FrequencyInfo{
+		Status:      FrequencyStatusUnsupported,
+		LastUpdated: time.Now(),
+	}
+
+	if err := getPlatformCPUFrequencyDetails(info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// IsPowerSaving 检查是否有核心处于节能调频策略
+func (f *FrequencyInfo) IsPowerSaving() bool {
+	for _, core := range f.Cores {
+		if core.Governor == "powersave" || core.Governor == "conservative" {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAppleSilicon 检查是否为Apple Silicon处理器
 func IsAppleSilicon() bool {
 	return runtime.GOOS == "darwin" && runtime.GOARCH == "arm64"
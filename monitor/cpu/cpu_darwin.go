@@ -71,6 +71,51 @@ func getPlatformCPUFrequency() (float64, error) {
 	return getDarwinCPUFrequency()
 }
 
+// getPlatformCPUFrequencyDetails 获取平台每核心频率信息
+func getPlatformCPUFrequencyDetails(info *FrequencyInfo) error {
+	return fmt.Errorf("per-core CPU frequency not supported on macOS")
+}
+
+// getPlatformCoreClasses 获取性能核心与效率核心对应的逻辑CPU编号（暂不支持）
+func getPlatformCoreClasses() ([]int, []int) {
+	return nil, nil
+}
+
+// getPlatformLoadInfo 获取负载平均值与任务数
+func getPlatformLoadInfo(usage *CPUUsage) error {
+	return fmt.Errorf("load info not supported on macOS")
+}
+
+// getPlatformTopology 获取CPU拓扑
+func getPlatformTopology(topology *Topology) error {
+	return fmt.Errorf("CPU topology not supported on macOS")
+}
+
+// getPlatformCPUFlags 获取CPU特性标志
+func getPlatformCPUFlags() ([]string, string, error) {
+	return getDarwinCPUFlags()
+}
+
+// getPlatformInterruptStats 获取中断与软中断计数
+func getPlatformInterruptStats(stats *InterruptStats) error {
+	return fmt.Errorf("interrupt statistics not supported on macOS")
+}
+
+// getPlatformVulnerabilities 获取CPU漏洞缓解状态
+func getPlatformVulnerabilities(report *VulnerabilityReport) error {
+	return fmt.Errorf("CPU vulnerability report not supported on macOS")
+}
+
+// getPlatformTemperatureDetails 获取平台CPU温度详细信息
+func getPlatformTemperatureDetails(info *TemperatureInfo) error {
+	temp, err := getDarwinCPUTemperature()
+	if err != nil {
+		return err
+	}
+	info.Package = temp
+	return nil
+}
+
 // getPlatformCPUUsage 获取平台CPU使用率
 func getPlatformCPUUsage() (*CPUUsage, error) {
 	return getCPUStatsFromHostInfo()
@@ -314,6 +359,38 @@ func getDarwinCPUFrequency() (float64, error) {
 	return 0, fmt.Errorf("CPU frequency not found")
 }
 
+// getDarwinCPUFlags 获取macOS CPU特性标志
+func getDarwinCPUFlags() ([]string, string, error) {
+	// Apple Silicon: hw.optional.arm.FEAT_* 映射为Linux风格的标志名称
+	if IsAppleSilicon() {
+		armFeatures := map[string]string{
+			"hw.optional.arm.FEAT_AES":    "aes",
+			"hw.optional.arm.FEAT_PMULL":  "pmull",
+			"hw.optional.arm.FEAT_SHA1":   "sha1",
+			"hw.optional.arm.FEAT_SHA256": "sha2",
+			"hw.optional.arm.FEAT_SHA512": "sha512",
+			"hw.optional.neon":            "asimd",
+		}
+
+		var flags []string
+		for name, flag := range armFeatures {
+			if value, err := sysctlUint64(name); err == nil && value == 1 {
+				flags = append(flags, flag)
+			}
+		}
+		return flags, "sysctl", nil
+	}
+
+	// Intel Mac: machdep.cpu.features 与 leaf7_features
+	cmd := exec.Command("sysctl", "-n", "machdep.cpu.features", "machdep.cpu.leaf7_features")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return strings.Fields(string(output)), "sysctl", nil
+}
+
 // getDarwinCPUTemperature 获取CPU温度
 func getDarwinCPUTemperature() (float64, error) {
 	// 尝试使用istats命令（如果安装了）
@@ -350,9 +427,9 @@ func getDarwinCPUTemperature() (float64, error) {
 }
 
 // getCPUStats 获取CPU统计信息
-func getCPUStats() (*CPUStats, error) {
+func getCPUStats() (*CPUStats, []*CPUStats, error) {
 	// 这个函数用于计算差值，暂时返回空实现
-	return &CPUStats{}, fmt.Errorf("use getPlatformCPUUsage instead")
+	return &CPUStats{}, nil, fmt.Errorf("use getPlatformCPUUsage instead")
 }
 
 // getPerCoreCPUUsage 获取每个核心的CPU使用率
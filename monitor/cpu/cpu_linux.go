@@ -3,53 +3,899 @@
 package cpu
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// Linux procfs/sysfs路径
+const (
+	procStatPath    = "/proc/stat"
+	procCPUInfoPath = "/proc/cpuinfo"
+	procLoadAvgPath = "/proc/loadavg"
+	sysCPUPath      = "/sys/devices/system/cpu"
+)
+
+// linuxSampleInterval Linux首次采样的等待时间
+const linuxSampleInterval = 250 * time.Millisecond
+
+var (
+	// 混合架构核心分类缓存（逻辑CPU编号）
+	coreClassesLoaded bool
+	performanceCPUs   []int
+	efficiencyCPUs    []int
 )
 
 // getPlatformCPUInfo 获取平台CPU信息
 func getPlatformCPUInfo(info *CPUInfo) error {
-	return fmt.Errorf("Linux CPU info not implemented yet")
-}
-
-// getPlatformCPUTemperature 获取平台CPU温度
-func getPlatformCPUTemperature() (float64, error) {
-	return 0, fmt.Errorf("Linux CPU temperature not implemented yet")
+	return getLinuxCPUInfo(info)
 }
 
 // getPlatformCPUFrequency 获取平台CPU频率
 func getPlatformCPUFrequency() (float64, error) {
-	return 0, fmt.Errorf("Linux CPU frequency not implemented yet")
+	return getLinuxCPUFrequency()
+}
+
+// getPlatformCPUFrequencyDetails 获取平台每核心频率信息
+func getPlatformCPUFrequencyDetails(info *FrequencyInfo) error {
+	return getLinuxCPUFrequencyDetails(info)
 }
 
 // getPlatformCPUUsage 获取平台CPU使用率
 func getPlatformCPUUsage() (*CPUUsage, error) {
-	return nil, fmt.Errorf("Linux CPU usage not implemented yet")
+	return GetUsageWithDuration(linuxSampleInterval)
+}
+
+// getPlatformCoreClasses 获取性能核心与效率核心对应的逻辑CPU编号
+func getPlatformCoreClasses() ([]int, []int) {
+	if !coreClassesLoaded {
+		performanceCPUs, efficiencyCPUs = classifyLinuxCores()
+		coreClassesLoaded = true
+	}
+	return performanceCPUs, efficiencyCPUs
 }
 
-// getLinuxCPUInfo 获取Linux CPU信息 (占位符实现)
+// getPlatformLoadInfo 从/proc/loadavg获取负载平均值与任务数，并按在线逻辑CPU数归一化
+func getPlatformLoadInfo(usage *CPUUsage) error {
+	loadavg, err := readSysfsString(procLoadAvgPath)
+	if err != nil {
+		return err
+	}
+
+	// 格式: "0.08 0.21 0.10 2/72 6561"
+	fields := strings.Fields(loadavg)
+	if len(fields) < 4 {
+		return fmt.Errorf("unexpected %s format: %q", procLoadAvgPath, loadavg)
+	}
+
+	loads := make([]float64, 3)
+	for i := range loads {
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return fmt.Errorf("failed to parse load average: %v", err)
+		}
+	}
+	usage.LoadAvg1, usage.LoadAvg5, usage.LoadAvg15 = loads[0], loads[1], loads[2]
+
+	if tasks := strings.SplitN(fields[3], "/", 2); len(tasks) == 2 {
+		if total, err := strconv.ParseUint(tasks[1], 10, 64); err == nil {
+			usage.TotalTasks = total
+		}
+	}
+
+	if cpus := onlineCPUCount(); cpus > 0 {
+		usage.LoadPerCPU1 = usage.LoadAvg1 / float64(cpus)
+		usage.LoadPerCPU5 = usage.LoadAvg5 / float64(cpus)
+		usage.LoadPerCPU15 = usage.LoadAvg15 / float64(cpus)
+	}
+
+	return nil
+}
+
+// onlineCPUCount 获取在线逻辑CPU数
+// PerCoreUsage按编号为离线核心保留了占位，不能用其长度；缺少sysfs时按/proc/stat中的cpuN行计数
+func onlineCPUCount() int {
+	if cpus, err := listOnlineCPUs(); err == nil && len(cpus) > 0 {
+		return len(cpus)
+	}
+
+	_, perCore, err := readProcStat()
+	if err != nil {
+		return runtime.NumCPU()
+	}
+	return countPresentCPUs(perCore)
+}
+
+// countPresentCPUs 统计/proc/stat中实际出现的核心数，离线核心的占位项计数全为0
+func countPresentCPUs(perCore []*CPUStats) int {
+	count := 0
+	for _, stats := range perCore {
+		if stats.Total > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// getLinuxCPUInfo 获取Linux CPU信息
 func getLinuxCPUInfo(info *CPUInfo) error {
-	return fmt.Errorf("Linux CPU info not implemented yet")
+	// 1. 解析/proc/cpuinfo获取型号、厂商等标识信息
+	processors, err := readCPUInfo()
+	if err != nil {
+		return fmt.Errorf("failed to get CPU info: %v", err)
+	}
+	if len(processors) > 0 {
+		parseCPUIdentity(info, processors)
+	}
+
+	// 2. 统计逻辑线程数与物理核心数
+	info.Threads = countLogicalCPUs(processors)
+	if cores := countPhysicalCores(processors); cores > 0 {
+		info.Cores = cores
+	} else {
+		info.Cores = info.Threads
+	}
+
+	// 3. 混合架构的性能核心与效率核心
+	if performance, efficiency := getPlatformCoreClasses(); len(performance) > 0 && len(efficiency) > 0 {
+		info.PerformanceCores = countCoresOf(performance)
+		info.EfficiencyCores = countCoresOf(efficiency)
+	}
+
+	// 4. 从sysfs获取缓存信息
+	getLinuxCacheInfo(info)
+
+	// 5. 获取频率信息
+	getLinuxFrequencyInfo(info, processors)
+
+	return nil
+}
+
+// cpuInfoBlock /proc/cpuinfo中的一个处理器段落
+type cpuInfoBlock map[string]string
+
+// readCPUInfo 读取/proc/cpuinfo，按空行拆分为段落
+func readCPUInfo() ([]cpuInfoBlock, error) {
+	file, err := os.Open(procCPUInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var blocks []cpuInfoBlock
+	current := cpuInfoBlock{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // flags行可能很长
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = cpuInfoBlock{}
+			}
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		current[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// processorBlocks 过滤出描述逻辑处理器的段落（ARM末尾的Hardware段落除外）
+func processorBlocks(blocks []cpuInfoBlock) []cpuInfoBlock {
+	var processors []cpuInfoBlock
+	for _, block := range blocks {
+		if _, ok := block["processor"]; ok {
+			processors = append(processors, block)
+		}
+	}
+	return processors
+}
+
+// cpuInfoValue 在所有段落中查找第一个非空字段值
+func cpuInfoValue(blocks []cpuInfoBlock, keys ...string) string {
+	for _, block := range blocks {
+		for _, key := range keys {
+			if value := block[key]; value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// parseCPUIdentity 解析型号、厂商与系列（兼容x86与ARM格式）
+func parseCPUIdentity(info *CPUInfo, blocks []cpuInfoBlock) {
+	// x86: vendor_id / cpu family / model name
+	if vendor := cpuInfoValue(blocks, "vendor_id"); vendor != "" {
+		info.Vendor = normalizeX86Vendor(vendor)
+		info.Family = cpuInfoValue(blocks, "cpu family")
+		info.Model = cpuInfoValue(blocks, "model name")
+		return
+	}
+
+	// ARM: CPU implementer / CPU architecture / CPU part
+	implementer := cpuInfoValue(blocks, "CPU implementer")
+	part := cpuInfoValue(blocks, "CPU part")
+	if implementer != "" {
+		info.Vendor = armImplementerName(implementer)
+	}
+	if arch := cpuInfoValue(blocks, "CPU architecture"); arch != "" {
+		if strings.HasPrefix(arch, "AArch") {
+			info.Family = arch
+		} else {
+			info.Family = "ARMv" + arch
+		}
+	}
+
+	// 部分ARM内核会输出model name（如 "ARMv7 Processor rev 4 (v7l)"），优先使用核心微架构名称
+	if name := armPartName(implementer, part); name != "" {
+		info.Model = strings.TrimSpace(info.Vendor + " " + name)
+	} else if model := cpuInfoValue(blocks, "model name", "Processor", "cpu model"); model != "" {
+		info.Model = model
+	}
+	if hardware := cpuInfoValue(blocks, "Hardware"); hardware != "" && info.Model == "" {
+		info.Model = hardware
+	}
+
+	// 其他架构（如MIPS路由器）
+	if info.Vendor == "" {
+		info.Vendor = cpuInfoValue(blocks, "system type", "vendor")
+	}
+}
+
+// normalizeX86Vendor 将CPUID厂商字符串转换为可读名称
+func normalizeX86Vendor(vendor string) string {
+	switch vendor {
+	case "GenuineIntel":
+		return "Intel"
+	case "AuthenticAMD":
+		return "AMD"
+	case "HygonGenuine":
+		return "Hygon"
+	case "CentaurHauls", "Shanghai":
+		return "Zhaoxin"
+	default:
+		return vendor
+	}
+}
+
+// armImplementerName ARM实现者编号到厂商名称
+func armImplementerName(implementer string) string {
+	names := map[string]string{
+		"0x41": "ARM",
+		"0x42": "Broadcom",
+		"0x43": "Cavium",
+		"0x46": "Fujitsu",
+		"0x48": "HiSilicon",
+		"0x4e": "NVIDIA",
+		"0x51": "Qualcomm",
+		"0x53": "Samsung",
+		"0x61": "Apple",
+		"0x69": "Intel",
+		"0x6d": "Microsoft",
+		"0xc0": "Ampere",
+	}
+	if name, ok := names[strings.ToLower(implementer)]; ok {
+		return name
+	}
+	return implementer
+}
+
+// armPartName ARM核心编号到微架构名称（仅覆盖常见型号）
+func armPartName(implementer, part string) string {
+	if implementer == "" || part == "" {
+		return ""
+	}
+
+	parts := map[string]string{
+		"0x41/0xc07": "Cortex-A7",
+		"0x41/0xc09": "Cortex-A9",
+		"0x41/0xc0f": "Cortex-A15",
+		"0x41/0xd03": "Cortex-A53",
+		"0x41/0xd04": "Cortex-A35",
+		"0x41/0xd05": "Cortex-A55",
+		"0x41/0xd07": "Cortex-A57",
+		"0x41/0xd08": "Cortex-A72",
+		"0x41/0xd09": "Cortex-A73",
+		"0x41/0xd0a": "Cortex-A75",
+		"0x41/0xd0b": "Cortex-A76",
+		"0x41/0xd0c": "Neoverse-N1",
+		"0x41/0xd0d": "Cortex-A77",
+		"0x41/0xd40": "Neoverse-V1",
+		"0x41/0xd41": "Cortex-A78",
+		"0x41/0xd44": "Cortex-X1",
+		"0x41/0xd46": "Cortex-A510",
+		"0x41/0xd47": "Cortex-A710",
+		"0x41/0xd48": "Cortex-X2",
+		"0x41/0xd49": "Neoverse-N2",
+		"0x41/0xd4f": "Neoverse-V2",
+		"0x48/0xd01": "TaiShan-v110",
+		"0xc0/0xac3": "Ampere-1",
+	}
+	return parts[strings.ToLower(implementer)+"/"+strings.ToLower(part)]
 }
 
-// getLinuxCPUTemperature 获取Linux CPU温度 (占位符实现)
-func getLinuxCPUTemperature() (float64, error) {
-	return 0, fmt.Errorf("Linux CPU temperature not implemented yet")
+// countLogicalCPUs 统计逻辑CPU数
+func countLogicalCPUs(blocks []cpuInfoBlock) int {
+	if cpus, err := listOnlineCPUs(); err == nil && len(cpus) > 0 {
+		return len(cpus)
+	}
+	return len(processorBlocks(blocks))
 }
 
-// getLinuxCPUFrequency 获取Linux CPU频率 (占位符实现)
+// countPhysicalCores 统计物理核心数（按 封装+核心 去重）
+func countPhysicalCores(blocks []cpuInfoBlock) int {
+	// 优先使用sysfs拓扑信息，ARM平台的/proc/cpuinfo不含core id
+	if cpus, err := listOnlineCPUs(); err == nil {
+		if cores := countCoresOf(cpus); cores > 0 {
+			return cores
+		}
+	}
+
+	// 备选：/proc/cpuinfo中的physical id与core id
+	cores := make(map[string]bool)
+	for _, block := range processorBlocks(blocks) {
+		core, ok := block["core id"]
+		if !ok {
+			return 0
+		}
+		cores[block["physical id"]+"/"+core] = true
+	}
+	return len(cores)
+}
+
+// countCoresOf 统计一组逻辑CPU所属的物理核心数，拓扑信息不可用时返回0
+func countCoresOf(cpus []int) int {
+	cores := make(map[string]bool)
+	for _, cpu := range cpus {
+		dir := filepath.Join(sysCPUPath, fmt.Sprintf("cpu%d", cpu), "topology")
+		pkg, err := readSysfsString(filepath.Join(dir, "physical_package_id"))
+		if err != nil {
+			return 0
+		}
+		core, err := readSysfsString(filepath.Join(dir, "core_id"))
+		if err != nil {
+			return 0
+		}
+		cores[pkg+"/"+core] = true
+	}
+	return len(cores)
+}
+
+// classifyLinuxCores 识别混合架构中的性能核心与效率核心
+// 依次尝试: /sys/devices/system/cpu/types (Intel混合架构)、
+// cpu_core/cpu_atom PMU、cpu_capacity (ARM big.LITTLE)
+func classifyLinuxCores() ([]int, []int) {
+	if performance, efficiency := classifyByCPUTypes(); len(performance) > 0 && len(efficiency) > 0 {
+		return performance, efficiency
+	}
+	if performance, efficiency := classifyByHybridPMU(); len(performance) > 0 && len(efficiency) > 0 {
+		return performance, efficiency
+	}
+	return classifyByCapacity()
+}
+
+// classifyByCPUTypes 根据 /sys/devices/system/cpu/types/intel_{core,atom}_* 分类
+func classifyByCPUTypes() ([]int, []int) {
+	dirs, err := filepath.Glob(filepath.Join(sysCPUPath, "types", "*"))
+	if err != nil {
+		return nil, nil
+	}
+
+	var performance, efficiency []int
+	for _, dir := range dirs {
+		list, err := readSysfsString(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			continue
+		}
+		cpus, err := procfs.ParseCPUList(list)
+		if err != nil {
+			continue
+		}
+
+		name := filepath.Base(dir)
+		switch {
+		case strings.Contains(name, "core"):
+			performance = append(performance, cpus...)
+		case strings.Contains(name, "atom"):
+			efficiency = append(efficiency, cpus...)
+		}
+	}
+
+	sort.Ints(performance)
+	sort.Ints(efficiency)
+	return performance, efficiency
+}
+
+// classifyByHybridPMU 根据混合架构的perf PMU (cpu_core/cpu_atom) 分类
+func classifyByHybridPMU() ([]int, []int) {
+	readPMUCPUs := func(name string) []int {
+		list, err := readSysfsString(filepath.Join("/sys/devices", name, "cpus"))
+		if err != nil {
+			return nil
+		}
+		cpus, err := procfs.ParseCPUList(list)
+		if err != nil {
+			return nil
+		}
+		return cpus
+	}
+
+	return readPMUCPUs("cpu_core"), readPMUCPUs("cpu_atom")
+}
+
+// classifyByCapacity 根据cpu_capacity分类，容量最大的为性能核心
+// 三丛集设计（如 X1+A78+A55）中除最小容量外均视为性能核心
+func classifyByCapacity() ([]int, []int) {
+	cpus, err := listOnlineCPUs()
+	if err != nil {
+		return nil, nil
+	}
+
+	capacities := make(map[int]int64)
+	minCapacity, maxCapacity := int64(-1), int64(-1)
+	for _, cpu := range cpus {
+		capacity, err := readSysfsInt(filepath.Join(sysCPUPath, fmt.Sprintf("cpu%d", cpu), "cpu_capacity"))
+		if err != nil {
+			return nil, nil
+		}
+		capacities[cpu] = capacity
+		if minCapacity < 0 || capacity < minCapacity {
+			minCapacity = capacity
+		}
+		if capacity > maxCapacity {
+			maxCapacity = capacity
+		}
+	}
+
+	// 所有核心容量一致，说明不是混合架构
+	if minCapacity == maxCapacity {
+		return nil, nil
+	}
+
+	var performance, efficiency []int
+	for _, cpu := range cpus {
+		if capacities[cpu] == minCapacity {
+			efficiency = append(efficiency, cpu)
+		} else {
+			performance = append(performance, cpu)
+		}
+	}
+	return performance, efficiency
+}
+
+// getLinuxCacheInfo 从sysfs读取cpu0所在各级缓存实例的大小
+func getLinuxCacheInfo(info *CPUInfo) {
+	dirs, err := filepath.Glob(filepath.Join(sysCPUPath, "cpu0", "cache", "index[0-9]*"))
+	if err != nil || len(dirs) == 0 {
+		return
+	}
+
+	for _, dir := range dirs {
+		level, err := readSysfsInt(filepath.Join(dir, "level"))
+		if err != nil {
+			continue
+		}
+		sizeStr, err := readSysfsString(filepath.Join(dir, "size"))
+		if err != nil {
+			continue
+		}
+		size, err := parseCacheSize(sizeStr)
+		if err != nil {
+			continue
+		}
+		cacheType, _ := readSysfsString(filepath.Join(dir, "type"))
+
+		switch level {
+		case 1:
+			switch cacheType {
+			case "Data":
+				info.CacheL1D += size
+			case "Instruction":
+				info.CacheL1I += size
+			default:
+				info.CacheL1D += size
+			}
+		case 2:
+			info.CacheL2 += size
+		case 3:
+			info.CacheL3 += size
+		}
+	}
+
+	info.CacheL1 = info.CacheL1D + info.CacheL1I
+}
+
+// parseCacheSize 解析sysfs缓存大小（如 "32K"、"8M"），返回KB
+func parseCacheSize(size string) (int, error) {
+	size = strings.TrimSpace(size)
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(size, "K"):
+		size = strings.TrimSuffix(size, "K")
+	case strings.HasSuffix(size, "M"):
+		size = strings.TrimSuffix(size, "M")
+		multiplier = 1024
+	case strings.HasSuffix(size, "G"):
+		size = strings.TrimSuffix(size, "G")
+		multiplier = 1024 * 1024
+	default:
+		// 无单位时按字节处理
+		value, err := strconv.Atoi(size)
+		return value / 1024, err
+	}
+
+	value, err := strconv.Atoi(size)
+	if err != nil {
+		return 0, err
+	}
+	return value * multiplier, nil
+}
+
+// modelFrequencyRegexp 匹配型号名称中的标称频率，如 "@ 2.10GHz"
+var modelFrequencyRegexp = regexp.MustCompile(`@\s*([\d.]+)\s*GHz`)
+
+// getLinuxFrequencyInfo 获取基础频率与最大频率 (GHz)
+func getLinuxFrequencyInfo(info *CPUInfo, blocks []cpuInfoBlock) {
+	cpufreqDir := filepath.Join(sysCPUPath, "cpu0", "cpufreq")
+
+	// 基础频率：intel_pstate/amd-pstate提供base_frequency，其次取型号中的标称频率
+	if base, err := readSysfsInt(filepath.Join(cpufreqDir, "base_frequency")); err == nil && base > 0 {
+		info.Frequency = float64(base) / 1000000 // kHz -> GHz
+	} else if matches := modelFrequencyRegexp.FindStringSubmatch(info.Model); len(matches) == 2 {
+		if freq, err := strconv.ParseFloat(matches[1], 64); err == nil {
+			info.Frequency = freq
+		}
+	} else if mhz := cpuInfoValue(blocks, "cpu MHz"); mhz != "" {
+		if freq, err := strconv.ParseFloat(mhz, 64); err == nil {
+			info.Frequency = freq / 1000
+		}
+	}
+
+	// 最大频率：取所有核心cpuinfo_max_freq的最大值（大小核的最大频率不同）
+	dirs, _ := filepath.Glob(filepath.Join(sysCPUPath, "cpu[0-9]*", "cpufreq", "cpuinfo_max_freq"))
+	for _, path := range dirs {
+		if ghz := readKHzAsGHz(path); ghz > info.MaxFrequency {
+			info.MaxFrequency = ghz
+		}
+	}
+}
+
+// listOnlineCPUs 获取在线逻辑CPU编号列表
+func listOnlineCPUs() ([]int, error) {
+	online, err := readSysfsString(filepath.Join(sysCPUPath, "online"))
+	if err != nil {
+		return nil, err
+	}
+	return procfs.ParseCPUList(online)
+}
+
+// readSysfsString 读取sysfs/procfs单值文件
+func readSysfsString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readSysfsInt 读取sysfs/procfs整数文件
+func readSysfsInt(path string) (int64, error) {
+	value, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// getLinuxCPUFrequency 获取Linux CPU当前平均频率 (GHz)
 func getLinuxCPUFrequency() (float64, error) {
-	return 0, fmt.Errorf("Linux CPU frequency not implemented yet")
+	info := &FrequencyInfo{}
+	if err := getLinuxCPUFrequencyDetails(info); err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	var count int
+	for _, core := range info.Cores {
+		if core.Current > 0 {
+			sum += core.Current
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("CPU frequency not found")
+	}
+
+	return sum / float64(count), nil
 }
 
-// getCPUStats 获取Linux CPU统计信息 (占位符实现)
-func getCPUStats() (*CPUStats, error) {
-	return &CPUStats{}, fmt.Errorf("Linux CPU stats not implemented yet")
+// getLinuxCPUFrequencyDetails 从cpufreq读取每个核心的频率与调频策略
+func getLinuxCPUFrequencyDetails(info *FrequencyInfo) error {
+	cpus := listFrequencyCPUs()
+
+	info.Status = FrequencyStatusUnsupported
+	info.Cores = make([]CoreFrequency, 0, len(cpus))
+
+	for _, cpu := range cpus {
+		core := CoreFrequency{CPU: cpu}
+		dir := filepath.Join(sysCPUPath, fmt.Sprintf("cpu%d", cpu), "cpufreq")
+
+		if _, err := os.Stat(dir); err == nil {
+			info.Status = FrequencyStatusSupported
+
+			core.Current = readKHzAsGHz(filepath.Join(dir, "scaling_cur_freq"))
+			if core.Current == 0 {
+				core.Current = readKHzAsGHz(filepath.Join(dir, "cpuinfo_cur_freq"))
+			}
+			core.Min = readKHzAsGHz(filepath.Join(dir, "scaling_min_freq"))
+			core.Max = readKHzAsGHz(filepath.Join(dir, "scaling_max_freq"))
+			core.HardwareMin = readKHzAsGHz(filepath.Join(dir, "cpuinfo_min_freq"))
+			core.HardwareMax = readKHzAsGHz(filepath.Join(dir, "cpuinfo_max_freq"))
+			core.Governor, _ = readSysfsString(filepath.Join(dir, "scaling_governor"))
+			core.Driver, _ = readSysfsString(filepath.Join(dir, "scaling_driver"))
+			if governors, err := readSysfsString(filepath.Join(dir, "scaling_available_governors")); err == nil {
+				core.AvailableGovernors = strings.Fields(governors)
+			}
+		}
+
+		info.Cores = append(info.Cores, core)
+	}
+
+	// 没有cpufreq时（虚拟机），退回/proc/cpuinfo中的当前频率
+	if info.Status == FrequencyStatusUnsupported {
+		fillFrequencyFromCPUInfo(info)
+	}
+
+	return nil
+}
+
+// listFrequencyCPUs 获取需要读取频率的逻辑CPU编号
+// 部分容器与旧内核没有online文件，退回sysfs中的cpuN目录，sysfs不可用时按runtime.NumCPU()编号
+func listFrequencyCPUs() []int {
+	if cpus, err := listOnlineCPUs(); err == nil && len(cpus) > 0 {
+		return cpus
+	}
+
+	var cpus []int
+	dirs, _ := filepath.Glob(filepath.Join(sysCPUPath, "cpu[0-9]*"))
+	for _, dir := range dirs {
+		if cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "cpu")); err == nil {
+			cpus = append(cpus, cpu)
+		}
+	}
+	if len(cpus) > 0 {
+		sort.Ints(cpus)
+		return cpus
+	}
+
+	for cpu := 0; cpu < runtime.NumCPU(); cpu++ {
+		cpus = append(cpus, cpu)
+	}
+	return cpus
 }
 
-// getPerCoreCPUUsage 获取Linux每个核心的CPU使用率 (占位符实现)
+// fillFrequencyFromCPUInfo 使用/proc/cpuinfo的cpu MHz填充当前频率
+func fillFrequencyFromCPUInfo(info *FrequencyInfo) {
+	blocks, err := readCPUInfo()
+	if err != nil {
+		return
+	}
+
+	current := make(map[int]float64)
+	for _, block := range processorBlocks(blocks) {
+		cpu, err := strconv.Atoi(block["processor"])
+		if err != nil {
+			continue
+		}
+		if mhz, err := strconv.ParseFloat(block["cpu MHz"], 64); err == nil {
+			current[cpu] = mhz / 1000
+		}
+	}
+
+	for i := range info.Cores {
+		info.Cores[i].Current = current[info.Cores[i].CPU]
+	}
+}
+
+// readKHzAsGHz 读取以kHz为单位的频率文件并转换为GHz，失败时返回0
+func readKHzAsGHz(path string) float64 {
+	value, err := readSysfsInt(path)
+	if err != nil {
+		return 0
+	}
+	return float64(value) / 1000000
+}
+
+// getCPUStats 从/proc/stat获取汇总与每核心CPU统计信息
+func getCPUStats() (*CPUStats, []*CPUStats, error) {
+	total, perCore, err := readProcStat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if total == nil {
+		return nil, nil, fmt.Errorf("cpu line not found in %s", procStatPath)
+	}
+	return total, perCore, nil
+}
+
+// getPerCoreCPUUsage 获取Linux每个核心的CPU使用率
 func getPerCoreCPUUsage(duration time.Duration) ([]float64, error) {
-	return nil, fmt.Errorf("Linux per-core CPU usage not implemented yet")
+	_, current, err := readProcStat()
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nil, fmt.Errorf("per-core cpu lines not found in %s", procStatPath)
+	}
+
+	// 第一次调用或CPU热插拔导致核心数变化时，重新采样
+	if len(lastPerCoreStats) != len(current) {
+		lastPerCoreStats = current
+		time.Sleep(duration)
+
+		_, current, err = readProcStat()
+		if err != nil {
+			return nil, err
+		}
+		if len(current) != len(lastPerCoreStats) {
+			lastPerCoreStats = current
+			return nil, fmt.Errorf("cpu count changed while sampling")
+		}
+	}
+
+	perCore := make([]float64, len(current))
+	for i := range current {
+		perCore[i] = calculateCPUUsage(lastPerCoreStats[i], current[i]).Overall
+	}
+
+	lastPerCoreStats = current
+
+	return perCore, nil
+}
+
+// readProcStat 读取/proc/stat
+func readProcStat() (*CPUStats, []*CPUStats, error) {
+	return readProcStatFile(procStatPath)
+}
+
+// readProcStatFile 解析stat格式文件，返回汇总统计与按核心编号排列的统计
+func readProcStatFile(path string) (*CPUStats, []*CPUStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var total *CPUStats
+	var perCore []*CPUStats
+	counters := make(map[string]uint64)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // 中断较多时intr行很长
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		// 进程与调度计数器
+		switch fields[0] {
+		case "ctxt", "processes", "procs_running", "procs_blocked":
+			if len(fields) >= 2 {
+				if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+					counters[fields[0]] = value
+				}
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		stats, err := parseCPUStatFields(fields[1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s line: %v", fields[0], err)
+		}
+
+		if fields[0] == "cpu" {
+			total = stats
+			continue
+		}
+
+		// 离线核心不会出现在/proc/stat中，按编号放置以保持索引稳定
+		index, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu"))
+		if err != nil || index < 0 {
+			continue
+		}
+		for len(perCore) <= index {
+			perCore = append(perCore, &CPUStats{})
+		}
+		perCore[index] = stats
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if total != nil {
+		total.ContextSwitches = counters["ctxt"]
+		total.Forks = counters["processes"]
+		total.ProcsRunning = counters["procs_running"]
+		total.ProcsBlocked = counters["procs_blocked"]
+	}
+
+	return total, perCore, nil
+}
+
+// parseCPUStatFields 解析/proc/stat中cpu行的数值字段
+// 字段顺序: user nice system idle iowait irq softirq steal guest guest_nice
+func parseCPUStatFields(fields []string) (*CPUStats, error) {
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("too few fields: %d", len(fields))
+	}
+
+	values := make([]uint64, 10)
+	for i := 0; i < len(fields) && i < len(values); i++ {
+		value, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	stats := &CPUStats{
+		User:      values[0],
+		Nice:      values[1],
+		System:    values[2],
+		Idle:      values[3],
+		IOWait:    values[4],
+		IRQ:       values[5],
+		SoftIRQ:   values[6],
+		Steal:     values[7],
+		Guest:     values[8],
+		GuestNice: values[9],
+	}
+
+	// 内核已将guest时间计入user/nice，这里扣除以免重复统计
+	user := stats.User
+	if user >= stats.Guest {
+		user -= stats.Guest
+	}
+	nice := stats.Nice
+	if nice >= stats.GuestNice {
+		nice -= stats.GuestNice
+	}
+	stats.User = user
+	stats.Nice = nice
+
+	stats.Total = stats.User + stats.Nice + stats.System + stats.Idle + stats.IOWait +
+		stats.IRQ + stats.SoftIRQ + stats.Steal + stats.Guest + stats.GuestNice
+
+	return stats, nil
 }
 
 // getAppleSiliconInfo Linux平台不支持Apple Silicon
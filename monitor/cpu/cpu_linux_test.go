@@ -0,0 +1,101 @@
+//go:build linux
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCPUStatFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []string
+		want    CPUStats
+		wantErr bool
+	}{
+		{
+			// guest/guest_nice已计入user/nice，需扣除
+			name:   "guest",
+			fields: []string{"1000", "200", "300", "4000", "50", "6", "7", "8", "400", "100"},
+			want: CPUStats{User: 600, Nice: 100, System: 300, Idle: 4000, IOWait: 50, IRQ: 6, SoftIRQ: 7, Steal: 8,
+				Guest: 400, GuestNice: 100, Total: 600 + 100 + 300 + 4000 + 50 + 6 + 7 + 8 + 400 + 100},
+		},
+		{
+			// 2.6.24之前的内核只有前几个字段
+			name:   "old kernel",
+			fields: []string{"10", "0", "5", "85"},
+			want:   CPUStats{User: 10, System: 5, Idle: 85, Total: 100},
+		},
+		{
+			// guest大于user时（计数器采样不一致）不扣除
+			name:   "guest exceeds user",
+			fields: []string{"10", "0", "5", "85", "0", "0", "0", "0", "20", "0"},
+			want:   CPUStats{User: 10, System: 5, Idle: 85, Guest: 20, Total: 120},
+		},
+		{name: "too few", fields: []string{"1", "2", "3"}, wantErr: true},
+		{name: "invalid", fields: []string{"1", "x", "3", "4"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		stats, err := parseCPUStatFields(test.fields)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if *stats != test.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", test.name, *stats, test.want)
+		}
+	}
+}
+
+func TestReadProcStatFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stat")
+	data := `cpu  3000 0 900 12000 30 0 20 0 600 0
+cpu0 1500 0 450 6000 10 0 10 0 300 0
+cpu2 1500 0 450 6000 20 0 10 0 300 0
+intr 123456 0 1 2
+ctxt 987654
+btime 1700000000
+processes 4321
+procs_running 3
+procs_blocked 1
+softirq 1000 0 1 2
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	total, perCore, err := readProcStatFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total.User != 2400 || total.Guest != 600 {
+		t.Errorf("total user=%d guest=%d, want 2400/600", total.User, total.Guest)
+	}
+	if total.ContextSwitches != 987654 || total.Forks != 4321 || total.ProcsRunning != 3 || total.ProcsBlocked != 1 {
+		t.Errorf("counters = %+v", *total)
+	}
+
+	// cpu1离线：保留占位以保持按编号索引
+	if len(perCore) != 3 {
+		t.Fatalf("expected 3 per-core slots, got %d", len(perCore))
+	}
+	if perCore[1].Total != 0 {
+		t.Errorf("offline cpu1 placeholder = %+v", *perCore[1])
+	}
+	if perCore[2].IOWait != 20 || perCore[2].User != 1200 {
+		t.Errorf("cpu2 = %+v", *perCore[2])
+	}
+	if got := countPresentCPUs(perCore); got != 2 {
+		t.Errorf("present cpus = %d, want 2", got)
+	}
+}
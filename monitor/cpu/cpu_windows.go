@@ -22,6 +22,46 @@ func getPlatformCPUFrequency() (float64, error) {
 	return 0, fmt.Errorf("Windows CPU frequency not implemented yet")
 }
 
+// getPlatformCPUFrequencyDetails 获取平台每核心频率信息
+func getPlatformCPUFrequencyDetails(info *FrequencyInfo) error {
+	return fmt.Errorf("per-core CPU frequency not supported on Windows")
+}
+
+// getPlatformCoreClasses 获取性能核心与效率核心对应的逻辑CPU编号（暂不支持）
+func getPlatformCoreClasses() ([]int, []int) {
+	return nil, nil
+}
+
+// getPlatformLoadInfo 获取负载平均值与任务数
+func getPlatformLoadInfo(usage *CPUUsage) error {
+	return fmt.Errorf("load info not supported on Windows")
+}
+
+// getPlatformTopology 获取CPU拓扑
+func getPlatformTopology(topology *Topology) error {
+	return fmt.Errorf("CPU topology not supported on Windows")
+}
+
+// getPlatformCPUFlags 获取CPU特性标志
+func getPlatformCPUFlags() ([]string, string, error) {
+	return nil, "", fmt.Errorf("CPU feature flags not supported on Windows")
+}
+
+// getPlatformInterruptStats 获取中断与软中断计数
+func getPlatformInterruptStats(stats *InterruptStats) error {
+	return fmt.Errorf("interrupt statistics not supported on Windows")
+}
+
+// getPlatformVulnerabilities 获取CPU漏洞缓解状态
+func getPlatformVulnerabilities(report *VulnerabilityReport) error {
+	return fmt.Errorf("CPU vulnerability report not supported on Windows")
+}
+
+// getPlatformTemperatureDetails 获取平台CPU温度详细信息
+func getPlatformTemperatureDetails(info *TemperatureInfo) error {
+	return fmt.Errorf("Windows CPU temperature not implemented yet")
+}
+
 // getPlatformCPUUsage 获取平台CPU使用率
 func getPlatformCPUUsage() (*CPUUsage, error) {
 	return nil, fmt.Errorf("Windows CPU usage not implemented yet")
@@ -38,8 +78,8 @@ func getWindowsCPUFrequency() (float64, error) {
 }
 
 // getCPUStats 获取Windows CPU统计信息 (占位符实现)
-func getCPUStats() (*CPUStats, error) {
-	return &CPUStats{}, fmt.Errorf("Windows CPU stats not implemented yet")
+func getCPUStats() (*CPUStats, []*CPUStats, error) {
+	return &CPUStats{}, nil, fmt.Errorf("Windows CPU stats not implemented yet")
 }
 
 // getPerCoreCPUUsage 获取Windows每个核心的CPU使用率 (占位符实现)
@@ -0,0 +1,119 @@
+package cpu
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 推荐的AEAD加密算法
+const (
+	AEADAESGCM           = "aes-gcm"           // AES-GCM（仅在Go有汇编实现的架构上且CPU支持AES与无进位乘法时才快）
+	AEADChaCha20Poly1305 = "chacha20-poly1305" // ChaCha20-Poly1305（纯软件实现也很快）
+)
+
+// CPUFeatures CPU指令集特性
+type CPUFeatures struct {
+	Architecture    string    `json:"architecture"`     // 架构 (amd64, arm64)
+	Flags           []string  `json:"flags"`            // 原始特性标志（小写，已排序）
+	AES             bool      `json:"aes"`              // AES硬件加速 (x86 AES-NI / ARMv8 AES)
+	CarrylessMul    bool      `json:"carryless_mul"`    // 无进位乘法 (x86 PCLMULQDQ / ARMv8 PMULL)，用于GHASH
+	VAES            bool      `json:"vaes"`             // 向量AES (x86 VAES)
+	VPCLMULQDQ      bool      `json:"vpclmulqdq"`       // 向量无进位乘法 (x86 VPCLMULQDQ)
+	SHA             bool      `json:"sha"`              // SHA-1/SHA-256硬件加速 (x86 SHA-NI / ARMv8 SHA1+SHA2)
+	SHA512          bool      `json:"sha512"`           // SHA-512硬件加速 (ARMv8.2 SHA512)
+	AVX             bool      `json:"avx"`              // AVX
+	AVX2            bool      `json:"avx2"`             // AVX2
+	AVX512          bool      `json:"avx512"`           // AVX-512 Foundation
+	SIMD            bool      `json:"simd"`             // ARM NEON/ASIMD
+	RecommendedAEAD string    `json:"recommended_aead"` // 推荐的AEAD算法
+	Reason          string    `json:"reason"`           // 推荐原因
+	Source          string    `json:"source"`           // 数据来源 (/proc/cpuinfo, sysctl)
+	LastUpdated     time.Time `json:"last_updated"`     // 最后更新时间
+}
+
+var (
+	cachedCPUFeatures *CPUFeatures
+)
+
+// GetFeatures 获取CPU指令集特性与推荐的AEAD算法（带缓存，特性在运行期间不会变化）
+func GetFeatures() (*CPUFeatures, error) {
+	if cachedCPUFeatures != nil {
+		return cachedCPUFeatures, nil
+	}
+
+	flags, source, err := getPlatformCPUFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	features := buildCPUFeatures(runtime.GOARCH, flags)
+	features.Source = source
+	features.LastUpdated = time.Now()
+
+	cachedCPUFeatures = features
+	return features, nil
+}
+
+// Has 检查是否包含指定的原始特性标志
+func (f *CPUFeatures) Has(flag string) bool {
+	flag = strings.ToLower(flag)
+	index := sort.SearchStrings(f.Flags, flag)
+	return index < len(f.Flags) && f.Flags[index] == flag
+}
+
+// buildCPUFeatures 根据原始标志归纳特性，并给出AEAD推荐
+func buildCPUFeatures(arch string, flags []string) *CPUFeatures {
+	features := &CPUFeatures{
+		Architecture: arch,
+	}
+
+	seen := make(map[string]bool)
+	for _, flag := range flags {
+		flag = strings.ToLower(strings.TrimSpace(flag))
+		if flag == "" || seen[flag] {
+			continue
+		}
+		seen[flag] = true
+		features.Flags = append(features.Flags, flag)
+	}
+	sort.Strings(features.Flags)
+
+	switch arch {
+	case "amd64", "386":
+		features.AES = seen["aes"]
+		features.CarrylessMul = seen["pclmulqdq"]
+		features.VAES = seen["vaes"]
+		features.VPCLMULQDQ = seen["vpclmulqdq"]
+		features.SHA = seen["sha_ni"] || seen["sha"]
+		features.AVX = seen["avx"] || seen["avx1.0"]
+		features.AVX2 = seen["avx2"]
+		features.AVX512 = seen["avx512f"]
+	case "arm64", "arm":
+		features.AES = seen["aes"]
+		features.CarrylessMul = seen["pmull"]
+		features.SHA = seen["sha1"] && seen["sha2"]
+		features.SHA512 = seen["sha512"]
+		features.SIMD = seen["asimd"] || seen["neon"]
+	}
+
+	// Go标准库的AES-GCM只在amd64、arm64（以及ppc64x、s390x）上有汇编实现，且需要AES与无进位乘法同时可用；
+	// 其他情况（包括CPU支持AES指令的386与32位arm）退化为常数时间的软件实现，比ChaCha20-Poly1305慢数倍
+	switch {
+	case arch != "amd64" && arch != "arm64":
+		features.RecommendedAEAD = AEADChaCha20Poly1305
+		features.Reason = "Go has no hardware-accelerated AES-GCM on " + arch
+	case features.AES && features.CarrylessMul:
+		features.RecommendedAEAD = AEADAESGCM
+		features.Reason = "hardware AES and carry-less multiplication available"
+	case features.AES:
+		features.RecommendedAEAD = AEADChaCha20Poly1305
+		features.Reason = "hardware AES available but GHASH lacks carry-less multiplication"
+	default:
+		features.RecommendedAEAD = AEADChaCha20Poly1305
+		features.Reason = "no hardware AES acceleration"
+	}
+
+	return features
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package cpu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getPlatformCPUFlags 从/proc/cpuinfo读取特性标志
+// x86使用flags字段，ARM使用Features字段
+func getPlatformCPUFlags() ([]string, string, error) {
+	blocks, err := readCPUInfo()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 混合架构各核心的特性一致，取第一个处理器段落即可
+	flags := cpuInfoValue(processorBlocks(blocks), "flags", "Features")
+	if flags == "" {
+		flags = cpuInfoValue(blocks, "flags", "Features")
+	}
+	if flags == "" {
+		return nil, "", fmt.Errorf("CPU flags not found in %s", procCPUInfoPath)
+	}
+
+	return strings.Fields(flags), procCPUInfoPath, nil
+}
@@ -0,0 +1,229 @@
+package cpu
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// InterruptSource 一条硬中断线（/proc/interrupts中的一行）
+type InterruptSource struct {
+	IRQ         string   `json:"irq"`         // 中断号或名称 (如 "24", "NMI", "LOC")
+	Chip        string   `json:"chip"`        // 中断控制器 (如 IR-PCI-MSI, GICv3)
+	Device      string   `json:"device"`      // 设备/处理程序名称 (如 eth0-TxRx-0)
+	Description string   `json:"description"` // 行尾的完整描述
+	PerCPU      []uint64 `json:"per_cpu"`     // 每个CPU的累计次数（与InterruptStats.CPUs对应）
+	Total       uint64   `json:"total"`       // 累计总次数
+}
+
+// SoftIRQSource 一类软中断（/proc/softirqs中的一行）
+type SoftIRQSource struct {
+	Name   string   `json:"name"`    // 软中断类型 (如 NET_RX, TIMER)
+	PerCPU []uint64 `json:"per_cpu"` // 每个CPU的累计次数（与InterruptStats.CPUs对应）
+	Total  uint64   `json:"total"`   // 累计总次数
+}
+
+// InterruptStats 中断与软中断累计计数
+type InterruptStats struct {
+	CPUs        []int             `json:"cpus"`         // 列对应的逻辑CPU编号
+	Interrupts  []InterruptSource `json:"interrupts"`   // 硬中断
+	SoftIRQs    []SoftIRQSource   `json:"soft_irqs"`    // 软中断
+	LastUpdated time.Time         `json:"last_updated"` // 采样时间
+}
+
+// InterruptRate 硬中断速率
+type InterruptRate struct {
+	IRQ    string    `json:"irq"`     // 中断号或名称
+	Device string    `json:"device"`  // 设备/处理程序名称
+	PerCPU []float64 `json:"per_cpu"` // 每个CPU的速率 (次/秒)
+	Total  float64   `json:"total"`   // 总速率 (次/秒)
+}
+
+// SoftIRQRate 软中断速率
+type SoftIRQRate struct {
+	Name   string    `json:"name"`    // 软中断类型
+	PerCPU []float64 `json:"per_cpu"` // 每个CPU的速率 (次/秒)
+	Total  float64   `json:"total"`   // 总速率 (次/秒)
+}
+
+// InterruptUsage 采样区间内的中断速率
+type InterruptUsage struct {
+	CPUs             []int           `json:"cpus"`               // 列对应的逻辑CPU编号
+	Interrupts       []InterruptRate `json:"interrupts"`         // 硬中断速率（按总速率降序）
+	SoftIRQs         []SoftIRQRate   `json:"soft_irqs"`          // 软中断速率
+	PerCPUInterrupts []float64       `json:"per_cpu_interrupts"` // 每个CPU的硬中断总速率
+	PerCPUSoftIRQs   []float64       `json:"per_cpu_soft_irqs"`  // 每个CPU的软中断总速率
+	Interval         time.Duration   `json:"interval"`           // 采样区间
+	LastUpdated      time.Time       `json:"last_updated"`       // 最后更新时间
+}
+
+var (
+	lastInterruptStats *InterruptStats
+)
+
+// GetInterruptStats 获取中断与软中断的累计计数
+func GetInterruptStats() (*InterruptStats, error) {
+	stats := &InterruptStats{}
+
+	if err := getPlatformInterruptStats(stats); err != nil {
+		return nil, err
+	}
+	stats.LastUpdated = time.Now()
+
+	return stats, nil
+}
+
+// GetInterruptRates 获取每个CPU的中断与软中断速率
+// 首次调用时等待duration采样，之后与上一次调用的结果比较
+func GetInterruptRates(duration time.Duration) (*InterruptUsage, error) {
+	current, err := GetInterruptStats()
+	if err != nil {
+		return nil, err
+	}
+
+	if lastInterruptStats == nil {
+		lastInterruptStats = current
+		time.Sleep(duration)
+
+		current, err = GetInterruptStats()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	usage := calculateInterruptRates(lastInterruptStats, current)
+	lastInterruptStats = current
+
+	return usage, nil
+}
+
+// calculateInterruptRates 计算两次采样之间的中断速率
+func calculateInterruptRates(last, current *InterruptStats) *InterruptUsage {
+	usage := &InterruptUsage{
+		CPUs:             current.CPUs,
+		PerCPUInterrupts: make([]float64, len(current.CPUs)),
+		PerCPUSoftIRQs:   make([]float64, len(current.CPUs)),
+		Interval:         current.LastUpdated.Sub(last.LastUpdated),
+		LastUpdated:      current.LastUpdated,
+	}
+
+	elapsed := usage.Interval.Seconds()
+	if elapsed <= 0 {
+		return usage
+	}
+
+	// CPU热插拔后列会变化，按CPU编号对齐
+	lastColumn := make(map[int]int, len(last.CPUs))
+	for i, cpu := range last.CPUs {
+		lastColumn[cpu] = i
+	}
+	perCPURates := func(lastCounts, currentCounts []uint64) ([]float64, float64) {
+		rates := make([]float64, len(current.CPUs))
+		var total float64
+		for i, cpu := range current.CPUs {
+			if i >= len(currentCounts) {
+				break
+			}
+			column, ok := lastColumn[cpu]
+			if !ok || column >= len(lastCounts) {
+				continue
+			}
+			rates[i] = float64(procfs.CounterDelta(lastCounts[column], currentCounts[i])) / elapsed
+			total += rates[i]
+		}
+		return rates, total
+	}
+
+	lastInterrupts := make(map[string]*InterruptSource, len(last.Interrupts))
+	for i := range last.Interrupts {
+		lastInterrupts[last.Interrupts[i].IRQ] = &last.Interrupts[i]
+	}
+	for _, source := range current.Interrupts {
+		previous, ok := lastInterrupts[source.IRQ]
+		if !ok {
+			continue
+		}
+		rates, total := perCPURates(previous.PerCPU, source.PerCPU)
+		usage.Interrupts = append(usage.Interrupts, InterruptRate{
+			IRQ:    source.IRQ,
+			Device: source.Device,
+			PerCPU: rates,
+			Total:  total,
+		})
+		for i, rate := range rates {
+			usage.PerCPUInterrupts[i] += rate
+		}
+	}
+	sort.SliceStable(usage.Interrupts, func(i, j int) bool {
+		return usage.Interrupts[i].Total > usage.Interrupts[j].Total
+	})
+
+	lastSoftIRQs := make(map[string]*SoftIRQSource, len(last.SoftIRQs))
+	for i := range last.SoftIRQs {
+		lastSoftIRQs[last.SoftIRQs[i].Name] = &last.SoftIRQs[i]
+	}
+	for _, source := range current.SoftIRQs {
+		previous, ok := lastSoftIRQs[source.Name]
+		if !ok {
+			continue
+		}
+		rates, total := perCPURates(previous.PerCPU, source.PerCPU)
+		usage.SoftIRQs = append(usage.SoftIRQs, SoftIRQRate{
+			Name:   source.Name,
+			PerCPU: rates,
+			Total:  total,
+		})
+		for i, rate := range rates {
+			usage.PerCPUSoftIRQs[i] += rate
+		}
+	}
+
+	return usage
+}
+
+// SoftIRQ 获取指定类型的软中断速率，如 "NET_RX"
+func (u *InterruptUsage) SoftIRQ(name string) *SoftIRQRate {
+	for i := range u.SoftIRQs {
+		if u.SoftIRQs[i].Name == name {
+			return &u.SoftIRQs[i]
+		}
+	}
+	return nil
+}
+
+// DeviceInterrupts 获取设备名称以prefix开头的硬中断（如 "eth0" 匹配 eth0-TxRx-0..N）
+func (u *InterruptUsage) DeviceInterrupts(prefix string) []InterruptRate {
+	var rates []InterruptRate
+	for _, rate := range u.Interrupts {
+		for _, device := range strings.Split(rate.Device, ",") {
+			if strings.HasPrefix(strings.TrimSpace(device), prefix) {
+				rates = append(rates, rate)
+				break
+			}
+		}
+	}
+	return rates
+}
+
+// Imbalance 计算每CPU速率的不均衡度（最大值 / 平均值）
+// 1表示完全均衡；值接近CPU数表示负载集中在单个CPU上（如RSS/IRQ亲和性未生效）
+func Imbalance(perCPU []float64) float64 {
+	if len(perCPU) == 0 {
+		return 0
+	}
+
+	var sum, max float64
+	for _, rate := range perCPU {
+		sum += rate
+		if rate > max {
+			max = rate
+		}
+	}
+	if sum == 0 {
+		return 0
+	}
+
+	return max / (sum / float64(len(perCPU)))
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package cpu
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 中断统计procfs路径
+const (
+	procInterruptsPath = "/proc/interrupts"
+	procSoftIRQsPath   = "/proc/softirqs"
+)
+
+// interruptTriggerRegexp 匹配中断触发类型字段，如 "0-edge"、"524288-edge"、"Level"、"fasteoi"
+var interruptTriggerRegexp = regexp.MustCompile(`(?i)(^|-)(edge|level|fasteoi)$`)
+
+// getPlatformInterruptStats 从/proc/interrupts与/proc/softirqs读取中断计数
+func getPlatformInterruptStats(stats *InterruptStats) error {
+	cpus, interrupts, err := readInterruptTable(procInterruptsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procInterruptsPath, err)
+	}
+	stats.CPUs = cpus
+
+	for _, row := range interrupts {
+		source := InterruptSource{
+			IRQ:    row.name,
+			PerCPU: row.counts,
+			Total:  sumCounts(row.counts),
+		}
+		parseInterruptDescription(&source, row.rest)
+		stats.Interrupts = append(stats.Interrupts, source)
+	}
+
+	// 软中断文件在极旧内核上不存在，不影响硬中断统计
+	softCPUs, softirqs, err := readInterruptTable(procSoftIRQsPath)
+	if err != nil {
+		return nil
+	}
+	column := make(map[int]int, len(softCPUs))
+	for i, cpu := range softCPUs {
+		column[cpu] = i
+	}
+	for _, row := range softirqs {
+		// 按硬中断表的CPU列对齐
+		counts := make([]uint64, len(cpus))
+		for i, cpu := range cpus {
+			if index, ok := column[cpu]; ok && index < len(row.counts) {
+				counts[i] = row.counts[index]
+			}
+		}
+		stats.SoftIRQs = append(stats.SoftIRQs, SoftIRQSource{
+			Name:   row.name,
+			PerCPU: counts,
+			Total:  sumCounts(counts),
+		})
+	}
+
+	return nil
+}
+
+// interruptRow 中断表中的一行
+type interruptRow struct {
+	name   string
+	counts []uint64
+	rest   []string
+}
+
+// readInterruptTable 解析 /proc/interrupts 或 /proc/softirqs 格式的表格
+// 首行为CPU列标题，之后每行为 "名称: 每CPU计数... 描述"
+func readInterruptTable(path string) ([]int, []interruptRow, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // CPU较多时行很长
+
+	if !scanner.Scan() {
+		return nil, nil, fmt.Errorf("empty file")
+	}
+	var cpus []int
+	for _, field := range strings.Fields(scanner.Text()) {
+		cpu, err := strconv.Atoi(strings.TrimPrefix(field, "CPU"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid header field %q", field)
+		}
+		cpus = append(cpus, cpu)
+	}
+
+	var rows []interruptRow
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+
+		row := interruptRow{name: strings.TrimSuffix(fields[0], ":")}
+		values := fields[1:]
+		// ERR/MIS等行只有一个总数，计入第一列
+		for i := 0; i < len(values) && i < len(cpus); i++ {
+			count, err := strconv.ParseUint(values[i], 10, 64)
+			if err != nil {
+				break
+			}
+			row.counts = append(row.counts, count)
+		}
+		row.rest = values[len(row.counts):]
+		for len(row.counts) < len(cpus) {
+			row.counts = append(row.counts, 0)
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return cpus, rows, nil
+}
+
+// parseInterruptDescription 从计数之后的字段中拆出中断控制器与设备名称
+// 数字中断: "IR-PCI-MSI 524288-edge eth0-TxRx-0" / "GICv3 27 Level arch_timer"
+// 命名中断: "Local timer interrupts"
+func parseInterruptDescription(source *InterruptSource, rest []string) {
+	source.Description = strings.Join(rest, " ")
+	if len(rest) == 0 {
+		return
+	}
+
+	if _, err := strconv.Atoi(source.IRQ); err != nil {
+		// LOC/NMI/RES等架构相关中断没有设备
+		source.Device = source.Description
+		return
+	}
+
+	source.Chip = rest[0]
+	for i := len(rest) - 1; i > 0; i-- {
+		if interruptTriggerRegexp.MatchString(rest[i]) {
+			source.Device = strings.Join(rest[i+1:], " ")
+			return
+		}
+	}
+
+	// 无法识别触发类型时，取最后一个字段作为设备名称
+	if len(rest) > 1 {
+		source.Device = rest[len(rest)-1]
+	}
+}
+
+// sumCounts 求和
+func sumCounts(counts []uint64) uint64 {
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+//go:build linux
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadInterruptTable(t *testing.T) {
+	// CPU1离线，不出现在标题行中
+	path := filepath.Join(t.TempDir(), "interrupts")
+	data := `           CPU0       CPU2       CPU3
+  0:         44          0          0   IO-APIC   2-edge      timer
+ 16:        120          3          0   IO-APIC  16-fasteoi   i801_smbus, ehci_hcd:usb1
+ 24:      12345          0          9   IR-PCI-MSI 524288-edge      eth0-TxRx-0
+ 27:        100        200        300     GICv3  27 Level     arch_timer
+NMI:          1          2          3   Non-maskable interrupts
+LOC:     100000      90000      80000   Local timer interrupts
+ERR:          0
+MIS:          5
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cpus, rows, err := readInterruptTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cpus, []int{0, 2, 3}) {
+		t.Fatalf("cpus = %v, want [0 2 3]", cpus)
+	}
+
+	want := []InterruptSource{
+		{IRQ: "0", Chip: "IO-APIC", Device: "timer", Description: "IO-APIC 2-edge timer", PerCPU: []uint64{44, 0, 0}, Total: 44},
+		{IRQ: "16", Chip: "IO-APIC", Device: "i801_smbus, ehci_hcd:usb1", Description: "IO-APIC 16-fasteoi i801_smbus, ehci_hcd:usb1", PerCPU: []uint64{120, 3, 0}, Total: 123},
+		{IRQ: "24", Chip: "IR-PCI-MSI", Device: "eth0-TxRx-0", Description: "IR-PCI-MSI 524288-edge eth0-TxRx-0", PerCPU: []uint64{12345, 0, 9}, Total: 12354},
+		{IRQ: "27", Chip: "GICv3", Device: "arch_timer", Description: "GICv3 27 Level arch_timer", PerCPU: []uint64{100, 200, 300}, Total: 600},
+		{IRQ: "NMI", Device: "Non-maskable interrupts", Description: "Non-maskable interrupts", PerCPU: []uint64{1, 2, 3}, Total: 6},
+		{IRQ: "LOC", Device: "Local timer interrupts", Description: "Local timer interrupts", PerCPU: []uint64{100000, 90000, 80000}, Total: 270000},
+		// ERR/MIS只有一个总数，计入第一列
+		{IRQ: "ERR", PerCPU: []uint64{0, 0, 0}},
+		{IRQ: "MIS", PerCPU: []uint64{5, 0, 0}, Total: 5},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(rows))
+	}
+	for i, row := range rows {
+		source := InterruptSource{IRQ: row.name, PerCPU: row.counts, Total: sumCounts(row.counts)}
+		parseInterruptDescription(&source, row.rest)
+		if !reflect.DeepEqual(source, want[i]) {
+			t.Errorf("row %d:\ngot  %+v\nwant %+v", i, source, want[i])
+		}
+	}
+}
+
+func TestParseInterruptDescription(t *testing.T) {
+	tests := []struct {
+		irq    string
+		rest   []string
+		chip   string
+		device string
+	}{
+		{"24", []string{"IR-PCI-MSI", "524288-edge", "eth0-TxRx-0"}, "IR-PCI-MSI", "eth0-TxRx-0"},
+		{"9", []string{"IO-APIC", "9-fasteoi", "acpi"}, "IO-APIC", "acpi"},
+		// 触发类型之后没有设备
+		{"7", []string{"IO-APIC", "7-edge"}, "IO-APIC", ""},
+		// 无法识别触发类型时取最后一个字段
+		{"50", []string{"PCI-MSIX-0000:00:04.0", "0", "virtio0-config"}, "PCI-MSIX-0000:00:04.0", "virtio0-config"},
+		{"RES", []string{"Rescheduling", "interrupts"}, "", "Rescheduling interrupts"},
+		{"ERR", nil, "", ""},
+	}
+
+	for _, test := range tests {
+		source := InterruptSource{IRQ: test.irq}
+		parseInterruptDescription(&source, test.rest)
+		if source.Chip != test.chip || source.Device != test.device {
+			t.Errorf("%s %v: chip=%q device=%q, want chip=%q device=%q", test.irq, test.rest, source.Chip, source.Device, test.chip, test.device)
+		}
+	}
+}
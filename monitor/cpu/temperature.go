@@ -0,0 +1,55 @@
+package cpu
+
+import (
+	"time"
+)
+
+// TemperatureSensor 单个温度传感器
+type TemperatureSensor struct {
+	Chip      string  `json:"chip"`       // 传感器芯片/区域名称 (coretemp, k10temp, cpu_thermal, x86_pkg_temp)
+	Label     string  `json:"label"`      // 传感器标签 (Package id 0, Core 3, Tctl)
+	Source    string  `json:"source"`     // 来源: hwmon, thermal
+	Path      string  `json:"path"`       // 读数文件路径
+	Current   float64 `json:"current"`    // 当前温度 (℃)
+	Max       float64 `json:"max"`        // 高温阈值 (℃)，未知为0
+	Critical  float64 `json:"critical"`   // 临界阈值 (℃)，未知为0
+	Core      int     `json:"core"`       // 对应的核心编号，非核心传感器为-1
+	PackageID int     `json:"package_id"` // 所属封装编号（coretemp的Package id），未知为-1
+	IsPackage bool    `json:"is_package"` // 是否为封装整体温度
+	IsCPU     bool    `json:"is_cpu"`     // 是否为CPU相关传感器
+}
+
+// TemperatureInfo CPU温度详细信息
+type TemperatureInfo struct {
+	Package              float64             `json:"package"`                // 封装温度 (℃)，无封装传感器时取核心最高温度
+	PackageSensor        string              `json:"package_sensor"`         // 封装温度的来源传感器
+	Max                  float64             `json:"max"`                    // 高温阈值 (℃)
+	Critical             float64             `json:"critical"`               // 临界阈值 (℃)
+	PerCore              []TemperatureSensor `json:"per_core"`               // 每个核心的温度，按(封装, 核心)排序
+	Sensors              []TemperatureSensor `json:"sensors"`                // 发现的全部温度传感器
+	CoreThrottleCount    uint64              `json:"core_throttle_count"`    // 核心因过热降频的累计次数
+	PackageThrottleCount uint64              `json:"package_throttle_count"` // 封装因过热降频的累计次数
+	LastUpdated          time.Time           `json:"last_updated"`           // 最后更新时间
+}
+
+// GetTemperatureDetails 获取CPU封装温度、每核心温度、阈值与传感器清单
+func GetTemperatureDetails() (*TemperatureInfo, error) {
+	info := &TemperatureInfo{
+		LastUpdated: time.Now(),
+	}
+
+	if err := getPlatformTemperatureDetails(info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// IsOverheating 检查封装温度是否达到高温阈值
+func (t *TemperatureInfo) IsOverheating() bool {
+	threshold := t.Max
+	if threshold == 0 {
+		threshold = t.Critical
+	}
+	return threshold > 0 && t.Package >= threshold
+}
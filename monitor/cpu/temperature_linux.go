@@ -0,0 +1,328 @@
+//go:build linux
+
+package cpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 温度传感器sysfs路径
+const (
+	sysHwmonPath   = "/sys/class/hwmon"
+	sysThermalPath = "/sys/class/thermal"
+)
+
+// cpuHwmonChips 与CPU相关的hwmon芯片名称
+var cpuHwmonChips = map[string]bool{
+	"coretemp":     true, // Intel
+	"k10temp":      true, // AMD
+	"k8temp":       true,
+	"zenpower":     true,
+	"cpu_thermal":  true, // 树莓派等ARM SoC
+	"cpu0_thermal": true,
+	"soc_thermal":  true,
+	"via_cputemp":  true,
+}
+
+// cpuThermalZones 与CPU相关的thermal zone类型（按优先级）
+var cpuThermalZones = []string{"x86_pkg_temp", "cpu-thermal", "cpu_thermal", "cpu0-thermal", "soc-thermal", "soc_thermal", "cpuss0-thermal"}
+
+// getPlatformCPUTemperature 获取平台CPU温度
+func getPlatformCPUTemperature() (float64, error) {
+	return getLinuxCPUTemperature()
+}
+
+// getPlatformTemperatureDetails 获取平台CPU温度详细信息
+func getPlatformTemperatureDetails(info *TemperatureInfo) error {
+	return getLinuxTemperatureDetails(info)
+}
+
+// getLinuxCPUTemperature 获取Linux CPU封装温度
+func getLinuxCPUTemperature() (float64, error) {
+	info := &TemperatureInfo{}
+	if err := getLinuxTemperatureDetails(info); err != nil {
+		return 0, err
+	}
+	return info.Package, nil
+}
+
+// getLinuxTemperatureDetails 扫描hwmon与thermal zone，选出封装温度与每核心温度
+func getLinuxTemperatureDetails(info *TemperatureInfo) error {
+	info.Sensors = append(readHwmonSensors(sysHwmonPath), readThermalZoneSensors()...)
+	if len(info.Sensors) == 0 {
+		return fmt.Errorf("no temperature sensors found in %s or %s", sysHwmonPath, sysThermalPath)
+	}
+
+	info.PerCore = perCoreSensors(info.Sensors)
+	pkg := selectPackageSensor(info.Sensors, info.PerCore)
+	if pkg == nil {
+		return fmt.Errorf("CPU temperature sensor not found")
+	}
+	info.Package = pkg.Current
+	info.PackageSensor = strings.TrimSpace(pkg.Chip + " " + pkg.Label)
+	info.Max = pkg.Max
+	info.Critical = pkg.Critical
+
+	// 封装传感器未提供阈值时，使用核心传感器的阈值
+	for _, core := range info.PerCore {
+		if info.Max == 0 && core.Max > 0 {
+			info.Max = core.Max
+		}
+		if info.Critical == 0 && core.Critical > 0 {
+			info.Critical = core.Critical
+		}
+	}
+
+	info.CoreThrottleCount, info.PackageThrottleCount = readThrottleCounts()
+
+	return nil
+}
+
+// perCoreSensors 取出每核心传感器，按(封装, 核心)排序
+// 多路CPU上每个封装有独立的coretemp设备，核心编号在各封装内从0开始
+func perCoreSensors(sensors []TemperatureSensor) []TemperatureSensor {
+	var perCore []TemperatureSensor
+	for _, sensor := range sensors {
+		if sensor.IsCPU && sensor.Core >= 0 {
+			perCore = append(perCore, sensor)
+		}
+	}
+	sort.SliceStable(perCore, func(i, j int) bool {
+		if perCore[i].PackageID != perCore[j].PackageID {
+			return perCore[i].PackageID < perCore[j].PackageID
+		}
+		return perCore[i].Core < perCore[j].Core
+	})
+	return perCore
+}
+
+// selectPackageSensor 选择代表CPU整体温度的传感器
+func selectPackageSensor(sensors, perCore []TemperatureSensor) *TemperatureSensor {
+	// 多路CPU取温度最高的封装
+	hottest := func(candidates []TemperatureSensor, match func(s *TemperatureSensor) bool) *TemperatureSensor {
+		var best *TemperatureSensor
+		for i := range candidates {
+			s := &candidates[i]
+			if match(s) && (best == nil || s.Current > best.Current) {
+				best = s
+			}
+		}
+		return best
+	}
+	pick := func(match func(s *TemperatureSensor) bool) *TemperatureSensor {
+		return hottest(sensors, match)
+	}
+
+	// 1. Intel coretemp "Package id N"
+	if s := pick(func(s *TemperatureSensor) bool { return s.Chip == "coretemp" && s.IsPackage }); s != nil {
+		return s
+	}
+	// 2. AMD Tdie（Tctl在部分型号上带有偏移）
+	if s := pick(func(s *TemperatureSensor) bool { return s.IsCPU && s.Label == "Tdie" }); s != nil {
+		return s
+	}
+	if s := pick(func(s *TemperatureSensor) bool { return s.IsCPU && s.Label == "Tctl" }); s != nil {
+		return s
+	}
+	// 3. CPU相关的thermal zone（按优先级）
+	for _, zone := range cpuThermalZones {
+		if s := pick(func(s *TemperatureSensor) bool { return s.Source == "thermal" && s.Chip == zone }); s != nil {
+			return s
+		}
+	}
+	// 4. 其余CPU hwmon芯片的首个传感器（如 cpu_thermal temp1）
+	if s := pick(func(s *TemperatureSensor) bool { return s.IsCPU && s.Source == "hwmon" && s.Core < 0 }); s != nil {
+		return s
+	}
+	// 5. 核心最高温度
+	if s := hottest(perCore, func(s *TemperatureSensor) bool { return true }); s != nil {
+		return s
+	}
+	// 6. ACPI温区（主板温度，聊胜于无）
+	return pick(func(s *TemperatureSensor) bool { return s.Chip == "acpitz" })
+}
+
+// readHwmonSensors 读取 <root>/hwmon*/temp*_input
+func readHwmonSensors(root string) []TemperatureSensor {
+	dirs, _ := filepath.Glob(filepath.Join(root, "hwmon*"))
+
+	var sensors []TemperatureSensor
+	for _, dir := range dirs {
+		sensors = append(sensors, readHwmonDevice(dir)...)
+	}
+
+	return sensors
+}
+
+// readHwmonDevice 读取单个hwmon设备的温度传感器
+// coretemp每个封装一个设备，核心传感器的封装编号取自同一设备的 "Package id N" 传感器
+func readHwmonDevice(dir string) []TemperatureSensor {
+	chip, err := readSysfsString(filepath.Join(dir, "name"))
+	if err != nil {
+		return nil
+	}
+
+	var sensors []TemperatureSensor
+	packageID := -1
+
+	// 旧内核的传感器文件位于device子目录
+	inputs, _ := filepath.Glob(filepath.Join(dir, "temp*_input"))
+	if len(inputs) == 0 {
+		inputs, _ = filepath.Glob(filepath.Join(dir, "device", "temp*_input"))
+	}
+
+	for _, input := range inputs {
+		current, err := readMilliCelsius(input)
+		if err != nil {
+			continue
+		}
+		prefix := strings.TrimSuffix(input, "_input")
+
+		sensor := TemperatureSensor{
+			Chip:    chip,
+			Source:  "hwmon",
+			Path:    input,
+			Current: current,
+			Core:    -1,
+			IsCPU:   cpuHwmonChips[chip],
+		}
+		sensor.Label, _ = readSysfsString(prefix + "_label")
+		if max, err := readMilliCelsius(prefix + "_max"); err == nil {
+			sensor.Max = max
+		}
+		if crit, err := readMilliCelsius(prefix + "_crit"); err == nil {
+			sensor.Critical = crit
+		}
+
+		// coretemp标签: "Package id 0" / "Core 3"
+		switch {
+		case strings.HasPrefix(sensor.Label, "Package id"), strings.HasPrefix(sensor.Label, "Physical id"):
+			sensor.IsPackage = true
+			fields := strings.Fields(sensor.Label)
+			if id, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+				packageID = id
+			}
+		case strings.HasPrefix(sensor.Label, "Core "):
+			if core, err := strconv.Atoi(strings.TrimPrefix(sensor.Label, "Core ")); err == nil {
+				sensor.Core = core
+			}
+		}
+
+		sensors = append(sensors, sensor)
+	}
+
+	for i := range sensors {
+		sensors[i].PackageID = packageID
+	}
+	return sensors
+}
+
+// readThermalZoneSensors 读取 /sys/class/thermal/thermal_zone*/temp
+func readThermalZoneSensors() []TemperatureSensor {
+	dirs, _ := filepath.Glob(filepath.Join(sysThermalPath, "thermal_zone*"))
+
+	var sensors []TemperatureSensor
+	for _, dir := range dirs {
+		zoneType, err := readSysfsString(filepath.Join(dir, "type"))
+		if err != nil {
+			continue
+		}
+		current, err := readMilliCelsius(filepath.Join(dir, "temp"))
+		if err != nil {
+			continue
+		}
+
+		sensor := TemperatureSensor{
+			Chip:      zoneType,
+			Label:     filepath.Base(dir),
+			Source:    "thermal",
+			Path:      filepath.Join(dir, "temp"),
+			Current:   current,
+			Core:      -1,
+			PackageID: -1,
+		}
+		for _, zone := range cpuThermalZones {
+			if zoneType == zone {
+				sensor.IsCPU = true
+				sensor.IsPackage = true
+			}
+		}
+
+		// 触发点: passive/hot 视为高温阈值，critical 视为临界阈值
+		trips, _ := filepath.Glob(filepath.Join(dir, "trip_point_*_type"))
+		for _, trip := range trips {
+			tripType, err := readSysfsString(trip)
+			if err != nil {
+				continue
+			}
+			temp, err := readMilliCelsius(strings.TrimSuffix(trip, "_type") + "_temp")
+			if err != nil || temp <= 0 {
+				continue
+			}
+			switch tripType {
+			case "critical":
+				sensor.Critical = temp
+			case "hot", "passive":
+				if sensor.Max == 0 || temp < sensor.Max {
+					sensor.Max = temp
+				}
+			}
+		}
+
+		sensors = append(sensors, sensor)
+	}
+
+	return sensors
+}
+
+// readThrottleCounts 汇总Intel thermal_throttle计数（每个物理核心/封装只计一次）
+func readThrottleCounts() (uint64, uint64) {
+	var coreCount, packageCount uint64
+
+	cpus, err := listOnlineCPUs()
+	if err != nil {
+		return 0, 0
+	}
+
+	seenCores := make(map[string]bool)
+	seenPackages := make(map[string]bool)
+	for _, cpu := range cpus {
+		cpuDir := filepath.Join(sysCPUPath, fmt.Sprintf("cpu%d", cpu))
+		dir := filepath.Join(cpuDir, "thermal_throttle")
+		if _, err := os.Stat(dir); err != nil {
+			return 0, 0
+		}
+
+		pkg, _ := readSysfsString(filepath.Join(cpuDir, "topology", "physical_package_id"))
+		core, _ := readSysfsString(filepath.Join(cpuDir, "topology", "core_id"))
+
+		if !seenCores[pkg+"/"+core] {
+			seenCores[pkg+"/"+core] = true
+			if count, err := readSysfsInt(filepath.Join(dir, "core_throttle_count")); err == nil {
+				coreCount += uint64(count)
+			}
+		}
+		if !seenPackages[pkg] {
+			seenPackages[pkg] = true
+			if count, err := readSysfsInt(filepath.Join(dir, "package_throttle_count")); err == nil {
+				packageCount += uint64(count)
+			}
+		}
+	}
+
+	return coreCount, packageCount
+}
+
+// readMilliCelsius 读取以毫摄氏度为单位的温度文件
+func readMilliCelsius(path string) (float64, error) {
+	value, err := readSysfsInt(path)
+	if err != nil {
+		return 0, err
+	}
+	return float64(value) / 1000, nil
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeHwmonFixture 创建一个hwmon设备目录，files为文件名到内容的映射
+func writeHwmonFixture(t *testing.T, root, name string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadHwmonSensorsPerPackage(t *testing.T) {
+	root := t.TempDir()
+	// 双路Intel: 每个封装一个coretemp设备，核心编号在封装内重复
+	writeHwmonFixture(t, root, "hwmon1", map[string]string{
+		"name":         "coretemp",
+		"temp1_input":  "55000",
+		"temp1_label":  "Package id 1",
+		"temp2_input":  "50000",
+		"temp2_label":  "Core 0",
+		"temp3_input":  "53000",
+		"temp3_label":  "Core 1",
+		"temp3_max":    "90000",
+		"temp3_crit":   "100000",
+		"temp10_input": "52000",
+		"temp10_label": "Core 8",
+	})
+	writeHwmonFixture(t, root, "hwmon0", map[string]string{
+		"name":        "coretemp",
+		"temp1_input": "48000",
+		"temp1_label": "Package id 0",
+		"temp2_input": "47000",
+		"temp2_label": "Core 0",
+		"temp3_input": "46000",
+		"temp3_label": "Core 1",
+	})
+	writeHwmonFixture(t, root, "hwmon2", map[string]string{
+		"name":        "acpitz",
+		"temp1_input": "27800",
+	})
+
+	sensors := readHwmonSensors(root)
+	if len(sensors) != 8 {
+		t.Fatalf("expected 8 sensors, got %d", len(sensors))
+	}
+
+	type coreKey struct {
+		pkg, core int
+	}
+	var got []coreKey
+	for _, sensor := range perCoreSensors(sensors) {
+		got = append(got, coreKey{sensor.PackageID, sensor.Core})
+	}
+	want := []coreKey{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {1, 8}}
+	if len(got) != len(want) {
+		t.Fatalf("per-core = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("per-core = %v, want %v", got, want)
+			break
+		}
+	}
+
+	for _, sensor := range sensors {
+		if sensor.Chip == "acpitz" && (sensor.PackageID != -1 || sensor.IsCPU) {
+			t.Errorf("acpitz sensor = %+v", sensor)
+		}
+		if sensor.Label == "Core 1" && sensor.PackageID == 1 && (sensor.Max != 90 || sensor.Critical != 100) {
+			t.Errorf("thresholds = %v/%v, want 90/100", sensor.Max, sensor.Critical)
+		}
+	}
+
+	pkg := selectPackageSensor(sensors, perCoreSensors(sensors))
+	if pkg == nil || pkg.Label != "Package id 1" {
+		t.Errorf("package sensor = %+v, want the hotter Package id 1", pkg)
+	}
+}
+
+func TestSelectPackageSensor(t *testing.T) {
+	sensor := func(chip, label, source string, current float64, core int, isCPU, isPackage bool) TemperatureSensor {
+		return TemperatureSensor{Chip: chip, Label: label, Source: source, Current: current, Core: core, PackageID: -1, IsCPU: isCPU, IsPackage: isPackage}
+	}
+
+	tests := []struct {
+		name    string
+		sensors []TemperatureSensor
+		want    string // 期望的Chip/Label，空表示nil
+	}{
+		{
+			name: "amd prefers Tdie over Tctl",
+			sensors: []TemperatureSensor{
+				sensor("k10temp", "Tctl", "hwmon", 70, -1, true, false),
+				sensor("k10temp", "Tdie", "hwmon", 43, -1, true, false),
+				sensor("acpitz", "", "hwmon", 90, -1, false, false),
+			},
+			want: "k10temp/Tdie",
+		},
+		{
+			name: "amd Tctl only",
+			sensors: []TemperatureSensor{
+				sensor("k10temp", "Tctl", "hwmon", 61, -1, true, false),
+				sensor("k10temp", "Tccd1", "hwmon", 58, -1, true, false),
+			},
+			want: "k10temp/Tctl",
+		},
+		{
+			// 先匹配的候选不应影响后续规则的选择
+			name: "thermal zone priority",
+			sensors: []TemperatureSensor{
+				sensor("soc-thermal", "thermal_zone1", "thermal", 80, -1, true, true),
+				sensor("cpu-thermal", "thermal_zone0", "thermal", 45, -1, true, true),
+			},
+			want: "cpu-thermal/thermal_zone0",
+		},
+		{
+			name: "generic cpu hwmon",
+			sensors: []TemperatureSensor{
+				sensor("cpu_thermal", "", "hwmon", 52, -1, true, false),
+				sensor("acpitz", "", "hwmon", 30, -1, false, false),
+			},
+			want: "cpu_thermal/",
+		},
+		{
+			name: "hottest core without package sensor",
+			sensors: []TemperatureSensor{
+				sensor("zenpower", "Core 0", "hwmon", 50, 0, true, false),
+				sensor("zenpower", "Core 1", "hwmon", 57, 1, true, false),
+				sensor("acpitz", "", "hwmon", 90, -1, false, false),
+			},
+			want: "zenpower/Core 1",
+		},
+		{
+			name:    "acpi fallback",
+			sensors: []TemperatureSensor{sensor("acpitz", "", "hwmon", 30, -1, false, false), sensor("nvme", "Composite", "hwmon", 40, -1, false, false)},
+			want:    "acpitz/",
+		},
+		{
+			name:    "none",
+			sensors: []TemperatureSensor{sensor("nvme", "Composite", "hwmon", 40, -1, false, false)},
+		},
+	}
+
+	for _, test := range tests {
+		got := selectPackageSensor(test.sensors, perCoreSensors(test.sensors))
+		switch {
+		case got == nil && test.want != "":
+			t.Errorf("%s: got nil, want %s", test.name, test.want)
+		case got != nil && got.Chip+"/"+got.Label != test.want:
+			t.Errorf("%s: got %s/%s, want %s", test.name, got.Chip, got.Label, test.want)
+		}
+	}
+}
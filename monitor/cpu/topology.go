@@ -0,0 +1,109 @@
+package cpu
+
+import (
+	"sort"
+	"time"
+)
+
+// Topology CPU拓扑结构：封装(socket) → 物理核心 → 逻辑CPU
+type Topology struct {
+	Packages      []CPUPackage `json:"packages"`       // CPU封装列表
+	NUMANodes     []NUMANode   `json:"numa_nodes"`     // NUMA节点列表
+	PhysicalCores int          `json:"physical_cores"` // 物理核心总数
+	LogicalCPUs   int          `json:"logical_cpus"`   // 逻辑CPU总数
+	SMTEnabled    bool         `json:"smt_enabled"`    // 是否启用超线程/SMT
+	LastUpdated   time.Time    `json:"last_updated"`   // 最后更新时间
+}
+
+// CPUPackage CPU封装（物理插槽）
+type CPUPackage struct {
+	ID    int            `json:"id"`    // 封装编号 (physical_package_id)
+	Cores []PhysicalCore `json:"cores"` // 物理核心列表
+}
+
+// PhysicalCore 物理核心
+type PhysicalCore struct {
+	ID          int          `json:"id"`           // 核心编号 (core_id，仅在封装内唯一)
+	DieID       int          `json:"die_id"`       // Die编号
+	ClusterID   int          `json:"cluster_id"`   // 丛集编号 (ARM)
+	NUMANode    int          `json:"numa_node"`    // 所属NUMA节点
+	LogicalCPUs []LogicalCPU `json:"logical_cpus"` // 该核心上的逻辑CPU（SMT兄弟线程）
+}
+
+// LogicalCPU 逻辑CPU（硬件线程）
+type LogicalCPU struct {
+	ID       int   `json:"id"`        // 逻辑CPU编号
+	NUMANode int   `json:"numa_node"` // 所属NUMA节点
+	Siblings []int `json:"siblings"`  // 同一物理核心上的其他逻辑CPU
+}
+
+// NUMANode NUMA节点
+type NUMANode struct {
+	ID        int   `json:"id"`        // 节点编号
+	CPUs      []int `json:"cpus"`      // 节点上的逻辑CPU
+	Distances []int `json:"distances"` // 到各节点的访问距离（按节点编号排列）
+}
+
+// GetTopology 获取CPU拓扑结构
+func GetTopology() (*Topology, error) {
+	topology := &Topology{
+		LastUpdated: time.Now(),
+	}
+
+	if err := getPlatformTopology(topology); err != nil {
+		return nil, err
+	}
+
+	// 汇总计数
+	topology.PhysicalCores = 0
+	topology.LogicalCPUs = 0
+	for _, pkg := range topology.Packages {
+		topology.PhysicalCores += len(pkg.Cores)
+		for _, core := range pkg.Cores {
+			topology.LogicalCPUs += len(core.LogicalCPUs)
+			if len(core.LogicalCPUs) > 1 {
+				topology.SMTEnabled = true
+			}
+		}
+	}
+
+	return topology, nil
+}
+
+// SiblingsOf 获取与指定逻辑CPU位于同一物理核心的其他逻辑CPU
+func (t *Topology) SiblingsOf(cpu int) []int {
+	for _, pkg := range t.Packages {
+		for _, core := range pkg.Cores {
+			for _, logical := range core.LogicalCPUs {
+				if logical.ID == cpu {
+					return logical.Siblings
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CPUsOfNode 获取指定NUMA节点上的逻辑CPU
+func (t *Topology) CPUsOfNode(node int) []int {
+	for _, n := range t.NUMANodes {
+		if n.ID == node {
+			return n.CPUs
+		}
+	}
+	return nil
+}
+
+// PrimaryThreads 获取每个物理核心的第一个逻辑CPU，常用于避开超线程兄弟进行绑核
+func (t *Topology) PrimaryThreads() []int {
+	var cpus []int
+	for _, pkg := range t.Packages {
+		for _, core := range pkg.Cores {
+			if len(core.LogicalCPUs) > 0 {
+				cpus = append(cpus, core.LogicalCPUs[0].ID)
+			}
+		}
+	}
+	sort.Ints(cpus)
+	return cpus
+}
@@ -0,0 +1,151 @@
+//go:build linux
+
+package cpu
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// sysNodePath NUMA节点sysfs路径
+const sysNodePath = "/sys/devices/system/node"
+
+// getPlatformTopology 从sysfs构建CPU拓扑
+func getPlatformTopology(topology *Topology) error {
+	cpus, err := listOnlineCPUs()
+	if err != nil {
+		return fmt.Errorf("failed to list online CPUs: %v", err)
+	}
+
+	// 1. NUMA节点与逻辑CPU的对应关系
+	nodes, cpuToNode := readNUMANodes()
+	topology.NUMANodes = nodes
+
+	// 2. 按 封装 → 核心 归类逻辑CPU
+	type coreKey struct {
+		pkg, die, core int
+	}
+	packages := make(map[int]map[coreKey]*PhysicalCore)
+
+	for _, cpu := range cpus {
+		dir := filepath.Join(sysCPUPath, fmt.Sprintf("cpu%d", cpu), "topology")
+
+		pkgID := readTopologyInt(filepath.Join(dir, "physical_package_id"))
+		dieID := readTopologyInt(filepath.Join(dir, "die_id"))
+		coreID := readTopologyInt(filepath.Join(dir, "core_id"))
+		clusterID := readTopologyInt(filepath.Join(dir, "cluster_id"))
+
+		logical := LogicalCPU{
+			ID:       cpu,
+			NUMANode: cpuToNode[cpu],
+		}
+		if siblings, err := readCPUListFile(filepath.Join(dir, "thread_siblings_list")); err == nil {
+			for _, sibling := range siblings {
+				if sibling != cpu {
+					logical.Siblings = append(logical.Siblings, sibling)
+				}
+			}
+		}
+
+		if packages[pkgID] == nil {
+			packages[pkgID] = make(map[coreKey]*PhysicalCore)
+		}
+		key := coreKey{pkg: pkgID, die: dieID, core: coreID}
+		core, ok := packages[pkgID][key]
+		if !ok {
+			core = &PhysicalCore{
+				ID:        coreID,
+				DieID:     dieID,
+				ClusterID: clusterID,
+				NUMANode:  logical.NUMANode,
+			}
+			packages[pkgID][key] = core
+		}
+		core.LogicalCPUs = append(core.LogicalCPUs, logical)
+	}
+
+	// 3. 输出按编号排序，保证结果稳定
+	pkgIDs := make([]int, 0, len(packages))
+	for id := range packages {
+		pkgIDs = append(pkgIDs, id)
+	}
+	sort.Ints(pkgIDs)
+
+	for _, id := range pkgIDs {
+		pkg := CPUPackage{ID: id}
+		for _, core := range packages[id] {
+			pkg.Cores = append(pkg.Cores, *core)
+		}
+		sort.Slice(pkg.Cores, func(i, j int) bool {
+			if pkg.Cores[i].DieID != pkg.Cores[j].DieID {
+				return pkg.Cores[i].DieID < pkg.Cores[j].DieID
+			}
+			return pkg.Cores[i].LogicalCPUs[0].ID < pkg.Cores[j].LogicalCPUs[0].ID
+		})
+		topology.Packages = append(topology.Packages, pkg)
+	}
+
+	return nil
+}
+
+// readNUMANodes 读取NUMA节点信息，返回节点列表与 逻辑CPU→节点 映射
+// 未启用NUMA的内核没有该目录，此时所有CPU视为节点0
+func readNUMANodes() ([]NUMANode, map[int]int) {
+	cpuToNode := make(map[int]int)
+
+	dirs, err := filepath.Glob(filepath.Join(sysNodePath, "node[0-9]*"))
+	if err != nil || len(dirs) == 0 {
+		return nil, cpuToNode
+	}
+
+	var nodes []NUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+
+		node := NUMANode{ID: id}
+		if cpus, err := readCPUListFile(filepath.Join(dir, "cpulist")); err == nil {
+			node.CPUs = cpus
+			for _, cpu := range cpus {
+				cpuToNode[cpu] = id
+			}
+		}
+		if distance, err := readSysfsString(filepath.Join(dir, "distance")); err == nil {
+			for _, field := range strings.Fields(distance) {
+				if value, err := strconv.Atoi(field); err == nil {
+					node.Distances = append(node.Distances, value)
+				}
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, cpuToNode
+}
+
+// readCPUListFile 读取CPU列表格式的sysfs文件
+func readCPUListFile(path string) ([]int, error) {
+	list, err := readSysfsString(path)
+	if err != nil {
+		return nil, err
+	}
+	return procfs.ParseCPUList(list)
+}
+
+// readTopologyInt 读取拓扑编号，文件不存在（旧内核无die_id/cluster_id）时返回0
+// 部分平台的physical_package_id为-1，同样归为0
+func readTopologyInt(path string) int {
+	value, err := readSysfsInt(path)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return int(value)
+}
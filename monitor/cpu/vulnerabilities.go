@@ -0,0 +1,166 @@
+package cpu
+
+import (
+	"strings"
+	"time"
+)
+
+// 漏洞状态
+const (
+	VulnerabilityVulnerable  = "vulnerable"   // 存在漏洞且未缓解
+	VulnerabilityMitigated   = "mitigated"    // 已缓解
+	VulnerabilityNotAffected = "not-affected" // 不受影响
+	VulnerabilityUnknown     = "unknown"      // 无法判断
+)
+
+// 缓解措施的性能开销等级（经验估计，实际开销取决于负载类型）
+const (
+	OverheadNone   = "none"
+	OverheadLow    = "low"
+	OverheadMedium = "medium"
+	OverheadHigh   = "high"
+)
+
+// Vulnerability 单个CPU漏洞的状态
+type Vulnerability struct {
+	Name                string `json:"name"`                 // 内核中的名称 (如 spectre_v2)
+	DisplayName         string `json:"display_name"`         // 通用名称 (如 Spectre v2)
+	State               string `json:"state"`                // 状态: vulnerable, mitigated, not-affected, unknown
+	Mitigation          string `json:"mitigation"`           // 缓解措施描述
+	PartiallyVulnerable bool   `json:"partially_vulnerable"` // 已缓解但仍有子项存在漏洞 (如 "BHI: Vulnerable")
+	Overhead            string `json:"overhead"`             // 缓解措施的预估性能开销
+	Raw                 string `json:"raw"`                  // 内核报告的原始文本
+}
+
+// VulnerabilityReport CPU漏洞与微码状态报告
+type VulnerabilityReport struct {
+	Vulnerabilities     []Vulnerability `json:"vulnerabilities"`      // 漏洞列表
+	Microcode           string          `json:"microcode"`            // 微码版本
+	SMTControl          string          `json:"smt_control"`          // SMT控制状态 (on, off, forceoff, notsupported)
+	MitigationsDisabled bool            `json:"mitigations_disabled"` // 是否通过 mitigations=off 全局关闭缓解
+	VulnerableCount     int             `json:"vulnerable_count"`     // 未缓解漏洞数（含部分缓解）
+	MitigatedCount      int             `json:"mitigated_count"`      // 已缓解漏洞数
+	NotAffectedCount    int             `json:"not_affected_count"`   // 不受影响漏洞数
+	LastUpdated         time.Time       `json:"last_updated"`         // 最后更新时间
+}
+
+// vulnerabilityDisplayNames 内核漏洞名称到通用名称
+var vulnerabilityDisplayNames = map[string]string{
+	"spectre_v1":                "Spectre v1",
+	"spectre_v2":                "Spectre v2",
+	"spec_store_bypass":         "Spectre v4 (SSB)",
+	"meltdown":                  "Meltdown",
+	"l1tf":                      "L1TF (Foreshadow)",
+	"mds":                       "MDS (ZombieLoad/RIDL)",
+	"tsx_async_abort":           "TAA",
+	"itlb_multihit":             "iTLB Multihit",
+	"srbds":                     "SRBDS (CrossTalk)",
+	"mmio_stale_data":           "MMIO Stale Data",
+	"retbleed":                  "Retbleed",
+	"gather_data_sampling":      "Downfall (GDS)",
+	"spec_rstack_overflow":      "Inception (SRSO)",
+	"reg_file_data_sampling":    "RFDS",
+	"indirect_target_selection": "ITS",
+	"tsa":                       "TSA",
+	"vmscape":                   "VMScape",
+	"old_microcode":             "Old Microcode",
+}
+
+// GetVulnerabilities 获取CPU漏洞缓解状态与微码版本
+func GetVulnerabilities() (*VulnerabilityReport, error) {
+	report := &VulnerabilityReport{
+		LastUpdated: time.Now(),
+	}
+
+	if err := getPlatformVulnerabilities(report); err != nil {
+		return nil, err
+	}
+
+	for _, v := range report.Vulnerabilities {
+		switch {
+		case v.State == VulnerabilityVulnerable || v.PartiallyVulnerable:
+			report.VulnerableCount++
+		case v.State == VulnerabilityMitigated:
+			report.MitigatedCount++
+		case v.State == VulnerabilityNotAffected:
+			report.NotAffectedCount++
+		}
+	}
+
+	return report, nil
+}
+
+// parseVulnerability 解析内核报告的漏洞状态文本
+// 格式: "Not affected" / "Vulnerable" / "Vulnerable: ..." / "Mitigation: ..." / "Unknown: ..."
+func parseVulnerability(name, raw string) Vulnerability {
+	raw = strings.TrimSpace(raw)
+	v := Vulnerability{
+		Name:        name,
+		DisplayName: vulnerabilityDisplayNames[name],
+		State:       VulnerabilityUnknown,
+		Overhead:    OverheadNone,
+		Raw:         raw,
+	}
+	if v.DisplayName == "" {
+		v.DisplayName = name
+	}
+
+	switch {
+	case strings.HasPrefix(raw, "Not affected"):
+		v.State = VulnerabilityNotAffected
+	case strings.HasPrefix(raw, "Vulnerable"):
+		v.State = VulnerabilityVulnerable
+		// 例如 "Vulnerable: Clear CPU buffers attempted, no microcode"
+		v.Mitigation = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(raw, "Vulnerable"), ":"))
+	case strings.HasPrefix(raw, "Mitigation:"):
+		v.State = VulnerabilityMitigated
+		v.Mitigation = strings.TrimSpace(strings.TrimPrefix(raw, "Mitigation:"))
+		// 子项大小写不一，如 "BHI: Vulnerable"、"SMT vulnerable"；
+		// 虚拟机中 "SMT Host state unknown" 表示无法确认宿主机已关闭SMT，同样按部分存在漏洞处理
+		mitigation := strings.ToLower(v.Mitigation)
+		v.PartiallyVulnerable = strings.Contains(mitigation, "vulnerable") || strings.Contains(mitigation, "state unknown")
+	}
+
+	if v.Mitigation != "" {
+		v.Overhead = estimateMitigationOverhead(name, v.Mitigation)
+	}
+
+	return v
+}
+
+// estimateMitigationOverhead 根据缓解方式估计性能开销
+// 对代理这类系统调用与上下文切换密集的负载，页表隔离与IBRS/IBPB类缓解影响最大
+func estimateMitigationOverhead(name, mitigation string) string {
+	m := strings.ToLower(mitigation)
+
+	switch {
+	case strings.Contains(m, "enhanced") && strings.Contains(m, "ibrs"):
+		return OverheadLow
+	case usesKernelIBRS(m),
+		strings.Contains(m, "untrained return thunk"),
+		strings.Contains(m, "ibpb on entry"),
+		strings.Contains(m, "safe ret"),
+		strings.Contains(m, "smt disabled"):
+		return OverheadHigh
+	case m == "pti",
+		strings.Contains(m, "retpoline"),
+		strings.Contains(m, "clear cpu buffers"),
+		strings.Contains(m, "cache flushes"),
+		strings.Contains(m, "microcode") && name == "srbds":
+		return OverheadMedium
+	default:
+		return OverheadLow
+	}
+}
+
+// usesKernelIBRS 判断缓解方式中是否启用了内核态IBRS（如 "IBRS" 或 "IBRS: ..."）
+// IBRS_FW只在固件调用时启用，开销可忽略，不能按子串匹配
+func usesKernelIBRS(mitigation string) bool {
+	for _, item := range strings.Split(mitigation, ";") {
+		item = strings.TrimSpace(item)
+		if item == "ibrs" || strings.HasPrefix(item, "ibrs:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package cpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// procCmdlinePath 内核启动参数
+const procCmdlinePath = "/proc/cmdline"
+
+// getPlatformVulnerabilities 读取 /sys/devices/system/cpu/vulnerabilities 与微码版本
+func getPlatformVulnerabilities(report *VulnerabilityReport) error {
+	dir := filepath.Join(sysCPUPath, "vulnerabilities")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", dir, err)
+	}
+
+	for _, entry := range entries {
+		raw, err := readSysfsString(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		report.Vulnerabilities = append(report.Vulnerabilities, parseVulnerability(entry.Name(), raw))
+	}
+	sort.Slice(report.Vulnerabilities, func(i, j int) bool {
+		return report.Vulnerabilities[i].Name < report.Vulnerabilities[j].Name
+	})
+
+	report.Microcode = readMicrocodeVersion()
+	report.SMTControl, _ = readSysfsString(filepath.Join(sysCPUPath, "smt", "control"))
+
+	if cmdline, err := readSysfsString(procCmdlinePath); err == nil {
+		for _, arg := range strings.Fields(cmdline) {
+			if arg == "mitigations=off" {
+				report.MitigationsDisabled = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// readMicrocodeVersion 读取微码版本，优先使用sysfs，其次/proc/cpuinfo
+func readMicrocodeVersion() string {
+	if version, err := readSysfsString(filepath.Join(sysCPUPath, "cpu0", "microcode", "version")); err == nil {
+		return version
+	}
+
+	blocks, err := readCPUInfo()
+	if err != nil {
+		return ""
+	}
+	return cpuInfoValue(processorBlocks(blocks), "microcode")
+}
@@ -0,0 +1,52 @@
+package cpu
+
+import "testing"
+
+func TestParseVulnerabilityPartiallyVulnerable(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		state   string
+		partial bool
+	}{
+		{"mds", "Mitigation: Clear CPU buffers; SMT vulnerable", VulnerabilityMitigated, true},
+		{"mmio_stale_data", "Mitigation: Clear CPU buffers; SMT Host state unknown", VulnerabilityMitigated, true},
+		{"spectre_v2", "Mitigation: Enhanced / Automatic IBRS; IBPB: conditional; RSB filling; PBRSB-eIBRS: SW sequence; BHI: Vulnerable", VulnerabilityMitigated, true},
+		{"tsx_async_abort", "Mitigation: Clear CPU buffers; SMT disabled", VulnerabilityMitigated, false},
+		{"meltdown", "Not affected", VulnerabilityNotAffected, false},
+		{"mds", "Vulnerable: Clear CPU buffers attempted, no microcode; SMT vulnerable", VulnerabilityVulnerable, false},
+	}
+
+	for _, test := range tests {
+		v := parseVulnerability(test.name, test.raw)
+		if v.State != test.state || v.PartiallyVulnerable != test.partial {
+			t.Errorf("%s %q: state=%s partial=%v, want state=%s partial=%v", test.name, test.raw, v.State, v.PartiallyVulnerable, test.state, test.partial)
+		}
+	}
+}
+
+func TestEstimateMitigationOverhead(t *testing.T) {
+	tests := []struct {
+		name       string
+		mitigation string
+		want       string
+	}{
+		{"spectre_v2", "Retpolines; IBPB: conditional; IBRS_FW; STIBP: conditional; RSB filling", OverheadMedium},
+		{"spectre_v2", "IBRS; IBPB: conditional; STIBP: disabled; RSB filling; PBRSB-eIBRS: Not affected", OverheadHigh},
+		{"retbleed", "IBRS", OverheadHigh},
+		{"spectre_v2", "Enhanced / Automatic IBRS; IBPB: conditional; RSB filling; PBRSB-eIBRS: SW sequence", OverheadLow},
+		{"spectre_v2", "Enhanced IBRS, IBPB: conditional, RSB filling", OverheadLow},
+		{"retbleed", "Untrained return thunk; SMT enabled with STIBP protection", OverheadHigh},
+		{"meltdown", "PTI", OverheadMedium},
+		{"mds", "Clear CPU buffers; SMT vulnerable", OverheadMedium},
+		{"srbds", "Microcode", OverheadMedium},
+		{"spectre_v1", "usercopy/swapgs barriers and __user pointer sanitization", OverheadLow},
+		{"spec_store_bypass", "Speculative Store Bypass disabled via prctl", OverheadLow},
+	}
+
+	for _, test := range tests {
+		if got := estimateMitigationOverhead(test.name, test.mitigation); got != test.want {
+			t.Errorf("%s %q: got %s, want %s", test.name, test.mitigation, got, test.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ package disk
 import (
 	"fmt"
 	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
 )
 
 // DiskInfo 磁盘基本信息
@@ -35,8 +37,29 @@ type DiskIOStats struct {
 	WeightedIOTime uint64    `json:"weighted_io_time"` // 加权I/O时间 (ms)
 	IopsInProgress uint64    `json:"iops_in_progress"` // 进行中的I/O操作数
 	LastUpdated    time.Time `json:"last_updated"`     // 最后更新时间
+
+	// 以下字段目前仅Linux提供
+	DeviceType       string `json:"device_type,omitempty"`        // 设备类型: disk, partition, dm
+	Parent           string `json:"parent,omitempty"`             // 分区所属的整块磁盘
+	Name             string `json:"name,omitempty"`               // device-mapper卷名，如vg0-root
+	LogicalBlockSize uint64 `json:"logical_block_size,omitempty"` // 逻辑扇区大小 (bytes)
+	ReadMerged       uint64 `json:"read_merged"`                  // 合并的读请求数
+	WriteMerged      uint64 `json:"write_merged"`                 // 合并的写请求数
+	DiscardCount     uint64 `json:"discard_count"`                // discard (TRIM) 次数，4.18+内核
+	DiscardMerged    uint64 `json:"discard_merged"`               // 合并的discard请求数
+	DiscardBytes     uint64 `json:"discard_bytes"`                // discard字节数
+	DiscardTime      uint64 `json:"discard_time"`                 // discard时间 (ms)
+	FlushCount       uint64 `json:"flush_count"`                  // flush次数，5.5+内核
+	FlushTime        uint64 `json:"flush_time"`                   // flush时间 (ms)
 }
 
+// 磁盘设备类型
+const (
+	DeviceTypeDisk      = "disk"      // 整块磁盘
+	DeviceTypePartition = "partition" // 分区
+	DeviceTypeDM        = "dm"        // device-mapper卷 (LVM、dm-crypt等)
+)
+
 // DiskSpeed 磁盘速度信息
 type DiskSpeed struct {
 	Device          string    `json:"device"`            // 设备名称
@@ -46,8 +69,22 @@ type DiskSpeed struct {
 	WriteIOPS       uint64    `json:"write_iops"`        // 写入IOPS
 	AvgReadLatency  float64   `json:"avg_read_latency"`  // 平均读延迟 (ms)
 	AvgWriteLatency float64   `json:"avg_write_latency"` // 平均写延迟 (ms)
-	Utilization     float64   `json:"utilization"`       // 利用率百分比
+	Utilization     float64   `json:"utilization"`       // 利用率百分比 (%util)
 	LastUpdated     time.Time `json:"last_updated"`      // 最后更新时间
+
+	// 以下字段与iostat -x含义一致，依赖WeightedIOTime、discard/flush计数器，目前仅Linux提供
+	// 排队与延迟的区分: AvgQueueSize高而await与请求大小正常说明请求在排队；await本身高说明设备慢
+	AvgQueueSize   float64 `json:"avg_queue_size"`   // 平均队列长度 (aqu-sz)
+	ReadAwait      float64 `json:"read_await"`       // 读请求平均耗时，含排队时间 (r_await, ms)
+	WriteAwait     float64 `json:"write_await"`      // 写请求平均耗时，含排队时间 (w_await, ms)
+	DiscardAwait   float64 `json:"discard_await"`    // discard请求平均耗时 (d_await, ms)
+	FlushAwait     float64 `json:"flush_await"`      // flush请求平均耗时 (f_await, ms)
+	AvgReadSize    float64 `json:"avg_read_size"`    // 读请求平均大小 (rareq-sz, bytes)
+	AvgWriteSize   float64 `json:"avg_write_size"`   // 写请求平均大小 (wareq-sz, bytes)
+	AvgDiscardSize float64 `json:"avg_discard_size"` // discard请求平均大小 (dareq-sz, bytes)
+	DiscardSpeed   uint64  `json:"discard_speed"`    // discard速度 (bytes/s)
+	DiscardIOPS    float64 `json:"discard_iops"`     // 每秒discard请求数 (d/s)
+	FlushIOPS      float64 `json:"flush_iops"`       // 每秒flush请求数 (f/s)
 }
 
 // DiskHealth 磁盘健康信息 (主要针对SSD/NVMe)
@@ -72,6 +109,13 @@ type DiskHealth struct {
 	LastUpdated       time.Time `json:"last_updated"`        // 最后更新时间
 }
 
+// DiskOptions 磁盘枚举选项
+type DiskOptions struct {
+	IncludePseudo     bool `json:"include_pseudo"`      // 包含proc、sysfs、cgroup、tmpfs、overlay等伪文件系统 (仅Linux)
+	IncludeBindMounts bool `json:"include_bind_mounts"` // 包含同一设备的重复挂载，如bind mount (仅Linux)
+	IncludeNetwork    bool `json:"include_network"`     // 包含nfs、cifs、sshfs等网络文件系统，服务端无响应的挂载超时后跳过 (仅Linux)
+}
+
 // PartitionInfo 分区信息
 type PartitionInfo struct {
 	Device        string `json:"device"`         // 设备名称
@@ -81,6 +125,16 @@ type PartitionInfo struct {
 	IsBootable    bool   `json:"is_bootable"`    // 是否可启动
 	IsSystem      bool   `json:"is_system"`      // 是否系统分区
 	PartitionType string `json:"partition_type"` // 分区类型
+
+	// 以下字段目前仅Linux提供，来自GPT/MBR分区表
+	Disk      string `json:"disk,omitempty"`       // 所属磁盘，如/dev/sda
+	Number    int    `json:"number,omitempty"`     // 分区号
+	Start     uint64 `json:"start,omitempty"`      // 起始偏移 (bytes)
+	Size      uint64 `json:"size,omitempty"`       // 大小 (bytes)
+	Label     string `json:"label,omitempty"`      // GPT分区名
+	PartUUID  string `json:"partuuid,omitempty"`   // 分区唯一标识，与/dev/disk/by-partuuid一致
+	TableType string `json:"table_type,omitempty"` // 分区表类型: gpt, mbr
+	TypeCode  string `json:"type_code,omitempty"`  // GPT类型GUID或MBR类型码
 }
 
 var (
@@ -88,13 +142,18 @@ var (
 	lastDiskIOStatsTime time.Time
 )
 
-// GetDisks 获取所有磁盘信息
+// GetDisks 获取所有磁盘信息，默认过滤伪文件系统、网络文件系统与重复挂载
 func GetDisks() ([]DiskInfo, error) {
+	return GetDisksWithOptions(DiskOptions{})
+}
+
+// GetDisksWithOptions 按选项获取磁盘信息
+func GetDisksWithOptions(options DiskOptions) ([]DiskInfo, error) {
 	var disks []DiskInfo
 
 	// 根据平台获取磁盘信息
 	var err error
-	disks, err = getPlatformDisks()
+	disks, err = getPlatformDisks(options)
 
 	if err != nil {
 		return nil, err
@@ -104,8 +163,10 @@ func GetDisks() ([]DiskInfo, error) {
 	now := time.Now()
 	for i := range disks {
 		disks[i].LastUpdated = now
-		// 计算使用率
-		if disks[i].Total > 0 {
+		// 计算使用率，与df一致按 已用/(已用+可用) 计算，不计入root保留块
+		if disks[i].Used+disks[i].Available > 0 {
+			disks[i].UsedPercent = float64(disks[i].Used) / float64(disks[i].Used+disks[i].Available) * 100
+		} else if disks[i].Total > 0 {
 			disks[i].UsedPercent = float64(disks[i].Used) / float64(disks[i].Total) * 100
 		}
 		// 计算inode使用率
@@ -173,7 +234,9 @@ func GetDiskSpeedWithInterval(interval time.Duration) ([]DiskSpeed, error) {
 			}
 		}
 	} else {
-		// 第一次调用，等待一个间隔后再次获取
+		// 第一次调用，记录本次统计并等待一个间隔后再次获取
+		lastDiskIOStats = currentStatsMap
+		lastDiskIOStatsTime = now
 		time.Sleep(interval)
 		return GetDiskSpeedWithInterval(interval)
 	}
@@ -185,48 +248,67 @@ func GetDiskSpeedWithInterval(interval time.Duration) ([]DiskSpeed, error) {
 	return speeds, nil
 }
 
-// calculateDiskSpeed 计算磁盘速度
+// calculateDiskSpeed 计算磁盘速度，计数器回绕或设备重置时对应增量按0处理
 func calculateDiskSpeed(last, current *DiskIOStats, timeDiff float64) DiskSpeed {
 	speed := DiskSpeed{
 		Device:      current.Device,
 		LastUpdated: current.LastUpdated,
 	}
+	if timeDiff <= 0 {
+		return speed
+	}
+
+	reads := procfs.CounterDelta(last.ReadCount, current.ReadCount)
+	writes := procfs.CounterDelta(last.WriteCount, current.WriteCount)
+	discards := procfs.CounterDelta(last.DiscardCount, current.DiscardCount)
+	flushes := procfs.CounterDelta(last.FlushCount, current.FlushCount)
+	readBytes := procfs.CounterDelta(last.ReadBytes, current.ReadBytes)
+	writeBytes := procfs.CounterDelta(last.WriteBytes, current.WriteBytes)
+	discardBytes := procfs.CounterDelta(last.DiscardBytes, current.DiscardBytes)
 
 	// 计算读写速度
-	if current.ReadBytes >= last.ReadBytes {
-		speed.ReadSpeed = uint64(float64(current.ReadBytes-last.ReadBytes) / timeDiff)
-	}
-	if current.WriteBytes >= last.WriteBytes {
-		speed.WriteSpeed = uint64(float64(current.WriteBytes-last.WriteBytes) / timeDiff)
-	}
+	speed.ReadSpeed = uint64(float64(readBytes) / timeDiff)
+	speed.WriteSpeed = uint64(float64(writeBytes) / timeDiff)
+	speed.DiscardSpeed = uint64(float64(discardBytes) / timeDiff)
 
 	// 计算IOPS
-	if current.ReadCount >= last.ReadCount {
-		speed.ReadIOPS = uint64(float64(current.ReadCount-last.ReadCount) / timeDiff)
-	}
-	if current.WriteCount >= last.WriteCount {
-		speed.WriteIOPS = uint64(float64(current.WriteCount-last.WriteCount) / timeDiff)
-	}
-
-	// 计算平均延迟
-	if speed.ReadIOPS > 0 && current.ReadTime >= last.ReadTime {
-		speed.AvgReadLatency = float64(current.ReadTime-last.ReadTime) / float64(current.ReadCount-last.ReadCount)
-	}
-	if speed.WriteIOPS > 0 && current.WriteTime >= last.WriteTime {
-		speed.AvgWriteLatency = float64(current.WriteTime-last.WriteTime) / float64(current.WriteCount-last.WriteCount)
-	}
+	speed.ReadIOPS = uint64(float64(reads) / timeDiff)
+	speed.WriteIOPS = uint64(float64(writes) / timeDiff)
+	speed.DiscardIOPS = float64(discards) / timeDiff
+	speed.FlushIOPS = float64(flushes) / timeDiff
+
+	// 计算平均延迟与请求大小，按区间内完成的请求数平均
+	speed.ReadAwait = averagePerRequest(procfs.CounterDelta(last.ReadTime, current.ReadTime), reads)
+	speed.WriteAwait = averagePerRequest(procfs.CounterDelta(last.WriteTime, current.WriteTime), writes)
+	speed.DiscardAwait = averagePerRequest(procfs.CounterDelta(last.DiscardTime, current.DiscardTime), discards)
+	speed.FlushAwait = averagePerRequest(procfs.CounterDelta(last.FlushTime, current.FlushTime), flushes)
+	speed.AvgReadLatency = speed.ReadAwait
+	speed.AvgWriteLatency = speed.WriteAwait
+
+	speed.AvgReadSize = averagePerRequest(readBytes, reads)
+	speed.AvgWriteSize = averagePerRequest(writeBytes, writes)
+	speed.AvgDiscardSize = averagePerRequest(discardBytes, discards)
+
+	// 平均队列长度: 加权I/O时间是每个请求在队列中停留时间之和
+	speed.AvgQueueSize = float64(procfs.CounterDelta(last.WeightedIOTime, current.WeightedIOTime)) / (timeDiff * 1000)
 
 	// 计算利用率
-	if current.IOTime >= last.IOTime {
-		speed.Utilization = float64(current.IOTime-last.IOTime) / (timeDiff * 1000) * 100
-		if speed.Utilization > 100 {
-			speed.Utilization = 100
-		}
+	speed.Utilization = float64(procfs.CounterDelta(last.IOTime, current.IOTime)) / (timeDiff * 1000) * 100
+	if speed.Utilization > 100 {
+		speed.Utilization = 100
 	}
 
 	return speed
 }
 
+// averagePerRequest 计算每个请求的平均值，没有请求时返回0
+func averagePerRequest(total, requests uint64) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(total) / float64(requests)
+}
+
 // GetDiskHealth 获取磁盘健康信息
 func GetDiskHealth() ([]DiskHealth, error) {
 	var healthInfo []DiskHealth
@@ -5,6 +5,7 @@ package disk
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -12,8 +13,8 @@ import (
 	"syscall"
 )
 
-// getPlatformDisks 获取平台磁盘信息
-func getPlatformDisks() ([]DiskInfo, error) {
+// getPlatformDisks 获取平台磁盘信息，macOS通过df获取，选项暂不生效
+func getPlatformDisks(options DiskOptions) ([]DiskInfo, error) {
 	return getDarwinDisks()
 }
 
@@ -406,8 +407,11 @@ func getDiskHealthForDevice(device string) *DiskHealth {
 
 // getSMARTInfo 获取SMART信息
 func getSMARTInfo(device string) map[string]string {
-	// 尝试使用smartctl（需要安装smartmontools）
-	cmd := exec.Command("smartctl", "-a", "/dev/"+device)
+	// 尝试使用smartctl（需要安装smartmontools），-n standby避免唤醒待机的磁盘
+	ctx, cancel := context.WithTimeout(context.Background(), smartctlTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, SmartctlPath, "-n", "standby", "-a", "/dev/"+device)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil
@@ -3,45 +3,745 @@
 package disk
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// Linux procfs/sysfs路径
+const (
+	procDiskstatsPath = "/proc/diskstats"
+	procSwapsPath     = "/proc/swaps"
+	sysDevBlockPath   = "/sys/dev/block"
+	sysClassBlockPath = "/sys/class/block"
+	sysBlockPath      = "/sys/block"
+	udevDataPath      = "/run/udev/data"
+)
+
+// diskstatsSectorSize /proc/diskstats中的扇区固定为512字节，与设备逻辑扇区大小无关
+const diskstatsSectorSize = 512
+
+// statfs标志位: 只读挂载
+const stRdonly = 0x1
+
+// pseudoFileSystems 默认不统计的伪文件系统，它们不占用磁盘或容量没有意义
+var pseudoFileSystems = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "devpts": true, "tmpfs": true, "ramfs": true,
+	"cgroup": true, "cgroup2": true, "cpuset": true, "mqueue": true, "hugetlbfs": true,
+	"debugfs": true, "tracefs": true, "securityfs": true, "pstore": true, "bpf": true,
+	"configfs": true, "fusectl": true, "efivarfs": true, "selinuxfs": true, "autofs": true,
+	"binfmt_misc": true, "rpc_pipefs": true, "nsfs": true, "nfsd": true,
+	"overlay": true, "aufs": true, // 容器镜像层，真实容量由底层文件系统提供
+	"squashfs":         true, // snap等只读镜像，始终100%占用
+	"fuse.lxcfs":       true,
+	"fuse.gvfsd-fuse":  true,
+	"fuse.portal":      true,
+	"fuse.snapfuse":    true,
+	"fuse.xdg-portals": true,
+}
+
+// networkFileSystems 默认不统计的网络文件系统，服务端无响应时statfs会一直阻塞
+var networkFileSystems = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true, "ncpfs": true,
+	"9p": true, "afs": true, "ceph": true, "glusterfs": true, "lustre": true, "gfs2": true,
+	"ocfs2": true, "davfs": true, "coda": true,
+	"fuse.sshfs":         true,
+	"fuse.rclone":        true,
+	"fuse.s3fs":          true,
+	"fuse.glusterfs":     true,
+	"fuse.ceph-fuse":     true,
+	"fuse.cephfs":        true,
+	"fuse.gcsfuse":       true,
+	"fuse.juicefs":       true,
+	"fuse.davfs2":        true,
+	"fuse.curlftpfs":     true,
+	"fuse.goofys":        true,
+	"fuse.mountpoint-s3": true,
+}
+
+// networkStatfsTimeout 网络文件系统单个挂载点statfs的超时时间
+const networkStatfsTimeout = 2 * time.Second
+
+// statfsInFlight 正在执行statfs的挂载点，超时后仍阻塞在内核中的挂载点在返回前不再创建新的goroutine
+var (
+	statfsInFlightMu sync.Mutex
+	statfsInFlight   = make(map[string]bool)
 )
 
 // getPlatformDisks 获取平台磁盘信息
-func getPlatformDisks() ([]DiskInfo, error) {
-	return nil, fmt.Errorf("Linux disk info not implemented yet")
+func getPlatformDisks(options DiskOptions) ([]DiskInfo, error) {
+	return getLinuxDisks(options)
 }
 
 // getPlatformDiskIOStats 获取平台磁盘I/O统计
 func getPlatformDiskIOStats() ([]DiskIOStats, error) {
-	return nil, fmt.Errorf("Linux disk IO stats not implemented yet")
+	return getLinuxDiskIOStats()
 }
 
 // getPlatformDiskHealth 获取平台磁盘健康信息
 func getPlatformDiskHealth() ([]DiskHealth, error) {
-	return nil, fmt.Errorf("Linux disk health not implemented yet")
+	return getLinuxDiskHealth()
 }
 
 // getPlatformPartitions 获取平台分区信息
 func getPlatformPartitions() ([]PartitionInfo, error) {
-	return nil, fmt.Errorf("Linux partitions not implemented yet")
+	return getLinuxPartitions()
+}
+
+// getLinuxDisks 根据/proc/self/mountinfo与statfs获取磁盘使用情况
+func getLinuxDisks(options DiskOptions) ([]DiskInfo, error) {
+	mounts, err := procfs.ReadMountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []DiskInfo
+	for _, mount := range filterMounts(mounts, options) {
+		var stat syscall.Statfs_t
+		if networkFileSystems[mount.FSType] {
+			if err := statfsWithTimeout(mount.Mountpoint, &stat, networkStatfsTimeout); err != nil {
+				continue
+			}
+		} else if err := syscall.Statfs(mount.Mountpoint, &stat); err != nil {
+			continue
+		}
+		if stat.Blocks == 0 && !options.IncludePseudo {
+			continue
+		}
+
+		// df使用f_frsize作为块大小
+		blockSize := uint64(stat.Frsize)
+		if blockSize == 0 {
+			blockSize = uint64(stat.Bsize)
+		}
+
+		disk := DiskInfo{
+			Device:     mountDevice(mount),
+			Mountpoint: mount.Mountpoint,
+			FileSystem: mount.FSType,
+			Total:      stat.Blocks * blockSize,
+			Available:  stat.Bavail * blockSize,
+			IsReadOnly: hasMountOption(mount.Options, "ro") || stat.Flags&stRdonly != 0,
+		}
+		if stat.Blocks >= stat.Bfree {
+			disk.Used = (stat.Blocks - stat.Bfree) * blockSize
+		}
+
+		// btrfs等文件系统不报告inode数量
+		if stat.Files > 0 && stat.Files >= stat.Ffree {
+			disk.InodesTotal = stat.Files
+			disk.InodesUsed = stat.Files - stat.Ffree
+		}
+
+		disks = append(disks, disk)
+	}
+
+	return disks, nil
+}
+
+// filterMounts 按选项去除重复挂载、伪文件系统与网络文件系统
+func filterMounts(mounts []procfs.MountInfo, options DiskOptions) []procfs.MountInfo {
+	if !options.IncludeBindMounts {
+		mounts = dedupeMounts(mounts)
+	}
+
+	var result []procfs.MountInfo
+	for _, mount := range mounts {
+		// 容器中根目录通常是overlay，仍需统计以发现根分区写满
+		if !options.IncludePseudo && pseudoFileSystems[mount.FSType] && mount.Mountpoint != "/" {
+			continue
+		}
+		if networkFileSystems[mount.FSType] && !options.IncludeNetwork {
+			continue
+		}
+		result = append(result, mount)
+	}
+	return result
+}
+
+// statfsWithTimeout 在超时时间内执行statfs
+// 挂载点无响应时statfs在内核中不可中断，超时后放弃等待，阻塞的goroutine在服务端恢复或挂载被卸载后退出；
+// 在此之前该挂载点直接跳过，避免周期性采集不断累积阻塞的goroutine
+func statfsWithTimeout(path string, stat *syscall.Statfs_t, timeout time.Duration) error {
+	type result struct {
+		stat syscall.Statfs_t
+		err  error
+	}
+
+	statfsInFlightMu.Lock()
+	if statfsInFlight[path] {
+		statfsInFlightMu.Unlock()
+		return fmt.Errorf("statfs %s is still in flight from a previous call", path)
+	}
+	statfsInFlight[path] = true
+	statfsInFlightMu.Unlock()
+
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		r.err = syscall.Statfs(path, &r.stat)
+
+		statfsInFlightMu.Lock()
+		delete(statfsInFlight, path)
+		statfsInFlightMu.Unlock()
+
+		done <- r
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		*stat = r.stat
+		return r.err
+	case <-timer.C:
+		return fmt.Errorf("statfs %s timed out after %v", path, timeout)
+	}
+}
+
+// systemMountpoints 视为系统分区的挂载点
+var systemMountpoints = map[string]bool{
+	"/": true, "/boot": true, "/boot/efi": true, "/efi": true, "/usr": true, "[SWAP]": true,
+}
+
+// getLinuxPartitions 从sysfs列出内核识别的分区，并与mountinfo、/proc/swaps关联得到挂载信息
+// 分区位置与大小取自/sys/class/block/<分区>/{partition,start,size}；类型、名称与启动标志优先取自udev数据库，
+// 只有缺少udev数据（容器、无udev的精简系统）时才直接读取磁盘分区表，此时跳过运行时挂起的磁盘以免将其唤醒。
+// ATA待机（hdparm -y）不会反映在runtime_status中，仍可能被读取唤醒；分区表扇区在内核扫描分区后通常仍在块设备页缓存中，
+// 且每块磁盘只读取一次、不超过几十个扇区，这种情况可以接受
+func getLinuxPartitions() ([]PartitionInfo, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysBlockPath, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, err := procfs.ReadMountInfo()
+	if err != nil {
+		return nil, err
+	}
+	mounts = dedupeMounts(mounts)
+	swaps := readActiveSwaps()
+
+	var partitions []PartitionInfo
+	for _, dir := range dirs {
+		disk := filepath.Base(dir)
+
+		var raw *PartitionTable
+		rawRead := false
+		for _, part := range readSysfsPartitions(dir) {
+			entry := PartitionEntry{Number: part.number, Start: part.start, Size: part.size}
+			table := &PartitionTable{}
+
+			props, _ := readUdevProperties(filepath.Join(udevDataPath, "b"+part.dev))
+			if tableType, ok := udevPartitionEntry(&entry, props); ok {
+				table.Type = tableType
+			} else {
+				if !rawRead {
+					raw = readRawPartitionTable(dir)
+					rawRead = true
+				}
+				if raw != nil {
+					table.Type = raw.Type
+					fillRawPartitionEntry(&entry, raw)
+				}
+			}
+
+			// 扩展分区只是逻辑分区的容器
+			if table.Type == PartitionTableMBR && entry.Number <= 4 && isExtendedPartition(parseMBRTypeCode(entry.Type)) {
+				continue
+			}
+			partitions = append(partitions, newLinuxPartitionInfo(disk, part.name, table, entry, mounts, swaps))
+		}
+	}
+
+	return partitions, nil
+}
+
+// sysfsPartition 内核识别的分区
+type sysfsPartition struct {
+	name   string // 分区设备名，如sda1
+	dev    string // 设备号，如8:1
+	number int    // 分区号
+	start  uint64 // 起始偏移 (bytes)
+	size   uint64 // 大小 (bytes)
+}
+
+// readSysfsPartitions 列出磁盘下内核识别的分区，按分区号排序
+// sysfs中的start与size与diskstats一样固定以512字节为单位
+func readSysfsPartitions(diskDir string) []sysfsPartition {
+	entries, err := os.ReadDir(diskDir)
+	if err != nil {
+		return nil
+	}
+
+	var partitions []sysfsPartition
+	for _, entry := range entries {
+		dir := filepath.Join(diskDir, entry.Name())
+		number, err := strconv.Atoi(readSysfsString(filepath.Join(dir, "partition")))
+		if err != nil {
+			continue
+		}
+		start, _ := strconv.ParseUint(readSysfsString(filepath.Join(dir, "start")), 10, 64)
+		size, _ := strconv.ParseUint(readSysfsString(filepath.Join(dir, "size")), 10, 64)
+
+		partitions = append(partitions, sysfsPartition{
+			name:   entry.Name(),
+			dev:    readSysfsString(filepath.Join(dir, "dev")),
+			number: number,
+			start:  start * diskstatsSectorSize,
+			size:   size * diskstatsSectorSize,
+		})
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].number < partitions[j].number })
+	return partitions
+}
+
+// readUdevProperties 读取udev数据库中设备的属性（"E:KEY=VALUE"行）
+func readUdevProperties(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	props := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.HasPrefix(key, "E:") {
+			props[strings.TrimPrefix(key, "E:")] = value
+		}
+	}
+	return props, nil
+}
+
+// udevPartitionEntry 根据udev的ID_PART_ENTRY_*属性填充分区类型、标识、名称与启动标志，返回分区表类型
+func udevPartitionEntry(entry *PartitionEntry, props map[string]string) (string, bool) {
+	flags, _ := strconv.ParseUint(strings.TrimPrefix(props["ID_PART_ENTRY_FLAGS"], "0x"), 16, 64)
+	entry.UUID = props["ID_PART_ENTRY_UUID"]
+	entry.Name = unescapeUdevValue(props["ID_PART_ENTRY_NAME"])
+
+	switch props["ID_PART_ENTRY_SCHEME"] {
+	case "gpt":
+		entry.Type = strings.ToLower(props["ID_PART_ENTRY_TYPE"])
+		entry.TypeName = gptPartitionTypes[entry.Type]
+		entry.Bootable = flags&gptBIOSBootableBit != 0 || entry.Type == gptTypeEFISystem
+		return PartitionTableGPT, true
+	case "dos":
+		// blkid输出"0x5"，统一为与分区表解析一致的"0x05"
+		code := parseMBRTypeCode(props["ID_PART_ENTRY_TYPE"])
+		entry.Type = fmt.Sprintf("0x%02x", code)
+		entry.TypeName = mbrPartitionTypes[code]
+		entry.Bootable = flags&0x80 != 0
+		return PartitionTableMBR, true
+	}
+	return "", false
+}
+
+// unescapeUdevValue 还原udev属性值中"\xNN"形式的转义字符
+func unescapeUdevValue(value string) string {
+	if !strings.Contains(value, "\\x") {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+3 < len(value) && value[i+1] == 'x' {
+			if c, err := strconv.ParseUint(value[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+// readRawPartitionTable 直接读取磁盘分区表，运行时挂起的磁盘返回nil
+func readRawPartitionTable(diskDir string) *PartitionTable {
+	if readSysfsString(filepath.Join(diskDir, "device", "power", "runtime_status")) == "suspended" {
+		return nil
+	}
+	table, err := ReadPartitionTable("/dev/" + filepath.Base(diskDir))
+	if err != nil {
+		return nil
+	}
+	return table
 }
 
-// getLinuxDisks 获取Linux磁盘信息 (占位符实现)
-func getLinuxDisks() ([]DiskInfo, error) {
-	return nil, fmt.Errorf("Linux disk info not implemented yet")
+// fillRawPartitionEntry 从分区表中按分区号补全类型、标识、名称与启动标志
+func fillRawPartitionEntry(entry *PartitionEntry, table *PartitionTable) {
+	for _, raw := range table.Partitions {
+		if raw.Number == entry.Number {
+			entry.Type = raw.Type
+			entry.TypeName = raw.TypeName
+			entry.UUID = raw.UUID
+			entry.Name = raw.Name
+			entry.Bootable = raw.Bootable
+			return
+		}
+	}
 }
 
-// getLinuxDiskIOStats 获取Linux磁盘I/O统计 (占位符实现)
+// newLinuxPartitionInfo 根据分区表项构造PartitionInfo，并按设备号或设备名关联挂载点
+func newLinuxPartitionInfo(disk, name string, table *PartitionTable, entry PartitionEntry, mounts []procfs.MountInfo, swaps map[string]bool) PartitionInfo {
+	partition := PartitionInfo{
+		Device:        "/dev/" + name,
+		Disk:          "/dev/" + disk,
+		Number:        entry.Number,
+		Start:         entry.Start,
+		Size:          entry.Size,
+		Label:         entry.Name,
+		PartUUID:      entry.UUID,
+		TableType:     table.Type,
+		TypeCode:      entry.Type,
+		PartitionType: entry.TypeName,
+		IsBootable:    entry.Bootable,
+	}
+	if partition.PartitionType == "" {
+		partition.PartitionType = entry.Type
+	}
+
+	// 内核未识别分区（如缺少分区表支持）时没有sysfs设备号，只能按设备名匹配
+	devNumber := readSysfsString(filepath.Join(sysClassBlockPath, name, "dev"))
+	for _, mount := range mounts {
+		if fmt.Sprintf("%d:%d", mount.Major, mount.Minor) == devNumber || mountDevice(mount) == partition.Device {
+			partition.Mountpoint = mount.Mountpoint
+			partition.FileSystem = mount.FSType
+			partition.Options = mount.Options
+			break
+		}
+	}
+	if partition.Mountpoint == "" && swaps[partition.Device] {
+		partition.Mountpoint = "[SWAP]"
+		partition.FileSystem = "swap"
+	}
+
+	partition.IsSystem = systemMountpoints[partition.Mountpoint] || entry.Type == gptTypeEFISystem || entry.Type == "0xef"
+	return partition
+}
+
+// partitionDeviceName 根据内核命名规则生成分区设备名: 磁盘名以数字结尾时加"p"，如nvme0n1p1、mmcblk0p1
+func partitionDeviceName(disk string, number int) string {
+	if last := disk[len(disk)-1]; last >= '0' && last <= '9' {
+		return fmt.Sprintf("%sp%d", disk, number)
+	}
+	return fmt.Sprintf("%s%d", disk, number)
+}
+
+// parseMBRTypeCode 解析"0x83"格式的MBR类型码
+func parseMBRTypeCode(code string) byte {
+	value, _ := strconv.ParseUint(strings.TrimPrefix(code, "0x"), 16, 8)
+	return byte(value)
+}
+
+// readActiveSwaps 读取/proc/swaps中正在使用的交换分区
+func readActiveSwaps() map[string]bool {
+	swaps := make(map[string]bool)
+	data, err := os.ReadFile(procSwapsPath)
+	if err != nil {
+		return swaps
+	}
+
+	// 首行为标题: Filename Type Size Used Priority
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[1] == "partition" {
+			swaps[procfs.UnescapePath(fields[0])] = true
+		}
+	}
+	return swaps
+}
+
+// getLinuxDiskIOStats 从/proc/diskstats读取块设备I/O统计，并从sysfs补充设备类型与扇区大小
+// 与iostat一样跳过从未发生过I/O的设备（如未使用的loop设备）
 func getLinuxDiskIOStats() ([]DiskIOStats, error) {
-	return nil, fmt.Errorf("Linux disk IO stats not implemented yet")
+	data, err := os.ReadFile(procDiskstatsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", procDiskstatsPath, err)
+	}
+
+	stats := parseDiskstats(string(data))
+	for i := range stats {
+		stat := &stats[i]
+		stat.DeviceType, stat.Parent = blockDeviceType(stat.Device)
+
+		switch stat.DeviceType {
+		case DeviceTypeDM:
+			if name, err := os.ReadFile(filepath.Join(sysClassBlockPath, stat.Device, "dm", "name")); err == nil {
+				stat.Name = strings.TrimSpace(string(name))
+			}
+		case DeviceTypePartition:
+			// 分区没有queue目录，扇区大小取自所属磁盘
+			stat.LogicalBlockSize = readBlockSize(stat.Parent)
+			continue
+		}
+		stat.LogicalBlockSize = readBlockSize(stat.Device)
+	}
+
+	return stats, nil
 }
 
-// getLinuxDiskHealth 获取Linux磁盘健康信息 (占位符实现)
+// getLinuxDiskHealth 从/sys/block/*/device读取磁盘标识，并通过smartctl --json补充SMART信息
+// 只包含有物理设备的磁盘（跳过loop、zram、dm等虚拟设备）；smartctl未安装或无权限时只返回sysfs信息
 func getLinuxDiskHealth() ([]DiskHealth, error) {
-	return nil, fmt.Errorf("Linux disk health not implemented yet")
+	dirs, err := filepath.Glob(filepath.Join(sysBlockPath, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var healthInfo []DiskHealth
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+			continue
+		}
+
+		// 与macOS一致，没有磨损指标（如机械硬盘或没有smartctl）时默认100%健康
+		health := readSysfsDiskHealth(dir)
+		health.HealthPercentage = 100
+		health.RemainingLife = 100
+		healthInfo = append(healthInfo, *health)
+	}
+
+	// 各磁盘并发执行smartctl并共用一个截止时间，单个无响应的磁盘不会拖慢整体
+	ctx, cancel := context.WithTimeout(context.Background(), smartctlTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range healthInfo {
+		wg.Add(1)
+		go func(health *DiskHealth) {
+			defer wg.Done()
+			getSmartctlHealth(ctx, "/dev/"+health.Device, health)
+		}(&healthInfo[i])
+	}
+	wg.Wait()
+
+	return healthInfo, nil
 }
 
-// getLinuxPartitions 获取Linux分区信息 (占位符实现)
-func getLinuxPartitions() ([]PartitionInfo, error) {
-	return nil, fmt.Errorf("Linux partition info not implemented yet")
+// readSysfsDiskHealth 从sysfs读取磁盘型号、序列号、固件版本、容量与接口类型
+// NVMe的device指向控制器（model, serial, firmware_rev），SCSI/ATA为scsi_device（vendor, model, rev）
+func readSysfsDiskHealth(dir string) *DiskHealth {
+	name := filepath.Base(dir)
+	device := filepath.Join(dir, "device")
+	health := &DiskHealth{
+		Device:    name,
+		Model:     readSysfsString(filepath.Join(device, "model")),
+		Serial:    readSysfsString(filepath.Join(device, "serial")),
+		Firmware:  readSysfsString(filepath.Join(device, "firmware_rev")),
+		Interface: blockDeviceInterface(dir),
+	}
+
+	if vendor := readSysfsString(filepath.Join(device, "vendor")); vendor != "" && vendor != "ATA" && !strings.HasPrefix(vendor, "0x") {
+		health.Model = strings.TrimSpace(vendor + " " + health.Model)
+	}
+	if health.Firmware == "" {
+		health.Firmware = readSysfsString(filepath.Join(device, "rev"))
+	}
+	if health.Serial == "" {
+		// virtio等驱动把序列号放在块设备目录下
+		health.Serial = readSysfsString(filepath.Join(dir, "serial"))
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "size")); err == nil {
+		sectors, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		health.Capacity = sectors * diskstatsSectorSize
+	}
+
+	return health
+}
+
+// blockDeviceInterface 根据sysfs设备路径判断接口类型
+func blockDeviceInterface(dir string) string {
+	target, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(target, "/nvme"):
+		return "NVMe"
+	case strings.Contains(target, "/usb"):
+		return "USB"
+	case strings.Contains(target, "/ata"):
+		return "SATA"
+	case strings.Contains(target, "/virtio"):
+		return "VirtIO"
+	case strings.Contains(target, "/mmc"):
+		return "MMC"
+	case strings.Contains(target, "/host"):
+		return "SCSI"
+	}
+	return ""
+}
+
+// readSysfsString 读取sysfs字符串属性，失败时返回空字符串
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseDiskstats 解析/proc/diskstats
+// 格式: major minor name reads rd_merged rd_sectors rd_ms writes wr_merged wr_sectors wr_ms in_flight io_ms weighted_ms
+// 4.18+追加 discards dc_merged dc_sectors dc_ms，5.5+追加 flushes fl_ms
+func parseDiskstats(data string) []DiskIOStats {
+	var stats []DiskIOStats
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 14 {
+			continue
+		}
+
+		var values [17]uint64
+		valid := true
+		for i, field := range fields[3:] {
+			if i >= len(values) {
+				break
+			}
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			values[i] = value
+		}
+		if !valid {
+			continue
+		}
+
+		idle := true
+		for _, value := range values {
+			if value != 0 {
+				idle = false
+				break
+			}
+		}
+		if idle {
+			continue
+		}
+
+		stats = append(stats, DiskIOStats{
+			Device:         fields[2],
+			ReadCount:      values[0],
+			ReadMerged:     values[1],
+			ReadBytes:      values[2] * diskstatsSectorSize,
+			ReadTime:       values[3],
+			WriteCount:     values[4],
+			WriteMerged:    values[5],
+			WriteBytes:     values[6] * diskstatsSectorSize,
+			WriteTime:      values[7],
+			IopsInProgress: values[8],
+			IOTime:         values[9],
+			WeightedIOTime: values[10],
+			DiscardCount:   values[11],
+			DiscardMerged:  values[12],
+			DiscardBytes:   values[13] * diskstatsSectorSize,
+			DiscardTime:    values[14],
+			FlushCount:     values[15],
+			FlushTime:      values[16],
+		})
+	}
+	return stats
+}
+
+// blockDeviceType 根据/sys/class/block判断设备类型，分区同时返回所属磁盘名
+func blockDeviceType(device string) (string, string) {
+	dir := filepath.Join(sysClassBlockPath, device)
+	if _, err := os.Stat(filepath.Join(dir, "partition")); err == nil {
+		// /sys/class/block/sda1 -> ../../devices/.../block/sda/sda1
+		if target, err := filepath.EvalSymlinks(dir); err == nil {
+			return DeviceTypePartition, filepath.Base(filepath.Dir(target))
+		}
+		return DeviceTypePartition, ""
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dm")); err == nil {
+		return DeviceTypeDM, ""
+	}
+	return DeviceTypeDisk, ""
+}
+
+// readBlockSize 读取设备逻辑扇区大小，失败时返回0
+func readBlockSize(device string) uint64 {
+	if device == "" {
+		return 0
+	}
+	data, err := os.ReadFile(filepath.Join(sysClassBlockPath, device, "queue", "logical_block_size"))
+	if err != nil {
+		return 0
+	}
+	size, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return size
+}
+
+// dedupeMounts 对同一设备的多次挂载（bind mount）只保留一条
+// 优先保留挂载文件系统根目录的记录，其次保留挂载点路径最短的记录
+func dedupeMounts(mounts []procfs.MountInfo) []procfs.MountInfo {
+	type deviceKey struct {
+		major, minor uint32
+	}
+
+	best := make(map[deviceKey]int)
+	for i, mount := range mounts {
+		key := deviceKey{mount.Major, mount.Minor}
+		j, exists := best[key]
+		if !exists {
+			best[key] = i
+			continue
+		}
+
+		current := mounts[j]
+		if (mount.Root == "/") != (current.Root == "/") {
+			if mount.Root == "/" {
+				best[key] = i
+			}
+			continue
+		}
+		if len(mount.Mountpoint) < len(current.Mountpoint) {
+			best[key] = i
+		}
+	}
+
+	var result []procfs.MountInfo
+	for i, mount := range mounts {
+		if best[deviceKey{mount.Major, mount.Minor}] == i {
+			result = append(result, mount)
+		}
+	}
+	return result
+}
+
+// mountDevice 返回挂载的设备名，/dev/root等别名通过设备号解析为真实设备
+func mountDevice(mount procfs.MountInfo) string {
+	if mount.Source != "/dev/root" {
+		return mount.Source
+	}
+
+	link, err := os.Readlink(filepath.Join(sysDevBlockPath, fmt.Sprintf("%d:%d", mount.Major, mount.Minor)))
+	if err != nil {
+		return mount.Source
+	}
+	return "/dev/" + filepath.Base(link)
+}
+
+// hasMountOption 检查逗号分隔的挂载选项中是否包含option
+func hasMountOption(options, option string) bool {
+	for _, value := range strings.Split(options, ",") {
+		if value == option {
+			return true
+		}
+	}
+	return false
 }
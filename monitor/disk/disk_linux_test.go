@@ -0,0 +1,493 @@
+//go:build linux
+
+package disk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// diskstatsSample 一对间隔采样的/proc/diskstats内容
+type diskstatsSample struct {
+	name     string
+	before   string
+	after    string
+	interval float64 // 采样间隔 (秒)
+	device   string
+	want     DiskSpeed
+}
+
+var diskstatsSamples = []diskstatsSample{
+	{
+		// 5.5+内核，20个字段，含discard与flush
+		name:     "virtio with discard and flush",
+		before:   " 254       0 vda 10783 3887 1421882 5923 10253 12781 1060280 8237 0 2360 14553 7952 0 249320 392 48 1\n",
+		after:    " 254       0 vda 10883 3897 1425882 6073 10653 12821 1069880 9437 2 2960 16053 7972 0 253320 412 58 6\n",
+		interval: 1,
+		device:   "vda",
+		want: DiskSpeed{
+			ReadSpeed:      2048000,
+			WriteSpeed:     4915200,
+			ReadIOPS:       100,
+			WriteIOPS:      400,
+			Utilization:    60,
+			AvgQueueSize:   1.5,
+			ReadAwait:      1.5,
+			WriteAwait:     3,
+			DiscardAwait:   1,
+			FlushAwait:     0.5,
+			AvgReadSize:    20480,
+			AvgWriteSize:   12288,
+			AvgDiscardSize: 102400,
+			DiscardSpeed:   2048000,
+			DiscardIOPS:    20,
+			FlushIOPS:      10,
+		},
+	},
+	{
+		// 4.18之前的内核只有14个字段；慢盘上的日志写入: 队列很深但单个请求很小
+		name: "old kernel queueing writes",
+		before: "   8       0 sda 5000 10 80000 4000 90000 100 2000000 600000 0 300000 700000\n" +
+			"   8       1 sda1 4900 10 79000 3900 89000 100 1990000 590000 0 290000 690000\n",
+		after: "   8       0 sda 5000 10 80000 4000 90400 180 2003200 608000 16 302000 732000\n" +
+			"   8       1 sda1 4900 10 79000 3900 89400 180 1993200 598000 16 292000 722000\n",
+		interval: 2,
+		device:   "sda",
+		want: DiskSpeed{
+			WriteSpeed:   819200,
+			WriteIOPS:    200,
+			Utilization:  100,
+			AvgQueueSize: 16,
+			WriteAwait:   20,
+			AvgWriteSize: 4096,
+		},
+	},
+	{
+		// 设备被移除后重新出现，计数器归零
+		name:     "counter reset",
+		before:   " 259       0 nvme0n1 900000 0 7200000 90000 800000 0 6400000 80000 0 100000 170000 0 0 0 0 0 0\n",
+		after:    " 259       0 nvme0n1 10 0 80 1 0 0 0 0 0 1 1 0 0 0 0 0 0\n",
+		interval: 1,
+		device:   "nvme0n1",
+		want:     DiskSpeed{},
+	},
+}
+
+func TestParseDiskstats(t *testing.T) {
+	stats := parseDiskstats(diskstatsSamples[0].before +
+		"   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n" +
+		"   8       0 sda 5000 10 80000 4000 90000 100 2000000 600000 3 300000 700000\n" +
+		"malformed line\n")
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 devices (idle loop0 skipped), got %d: %+v", len(stats), stats)
+	}
+
+	vda := stats[0]
+	if vda.Device != "vda" || vda.ReadCount != 10783 || vda.ReadMerged != 3887 || vda.ReadBytes != 1421882*512 ||
+		vda.ReadTime != 5923 || vda.WriteCount != 10253 || vda.WriteMerged != 12781 || vda.WriteBytes != 1060280*512 ||
+		vda.WriteTime != 8237 || vda.IopsInProgress != 0 || vda.IOTime != 2360 || vda.WeightedIOTime != 14553 ||
+		vda.DiscardCount != 7952 || vda.DiscardBytes != 249320*512 || vda.DiscardTime != 392 ||
+		vda.FlushCount != 48 || vda.FlushTime != 1 {
+		t.Errorf("unexpected vda stats: %+v", vda)
+	}
+
+	sda := stats[1]
+	if sda.Device != "sda" || sda.IopsInProgress != 3 || sda.WeightedIOTime != 700000 || sda.DiscardCount != 0 || sda.FlushCount != 0 {
+		t.Errorf("unexpected sda stats: %+v", sda)
+	}
+}
+
+func TestCalculateDiskSpeedFromDiskstats(t *testing.T) {
+	for _, sample := range diskstatsSamples {
+		t.Run(sample.name, func(t *testing.T) {
+			last := findDiskstats(t, sample.before, sample.device)
+			current := findDiskstats(t, sample.after, sample.device)
+
+			got := calculateDiskSpeed(&last, &current, sample.interval)
+			want := sample.want
+			want.Device = sample.device
+			want.AvgReadLatency = want.ReadAwait
+			want.AvgWriteLatency = want.WriteAwait
+
+			checkUint(t, "ReadSpeed", got.ReadSpeed, want.ReadSpeed)
+			checkUint(t, "WriteSpeed", got.WriteSpeed, want.WriteSpeed)
+			checkUint(t, "ReadIOPS", got.ReadIOPS, want.ReadIOPS)
+			checkUint(t, "WriteIOPS", got.WriteIOPS, want.WriteIOPS)
+			checkUint(t, "DiscardSpeed", got.DiscardSpeed, want.DiscardSpeed)
+			checkFloat(t, "Utilization", got.Utilization, want.Utilization)
+			checkFloat(t, "AvgQueueSize", got.AvgQueueSize, want.AvgQueueSize)
+			checkFloat(t, "ReadAwait", got.ReadAwait, want.ReadAwait)
+			checkFloat(t, "WriteAwait", got.WriteAwait, want.WriteAwait)
+			checkFloat(t, "DiscardAwait", got.DiscardAwait, want.DiscardAwait)
+			checkFloat(t, "FlushAwait", got.FlushAwait, want.FlushAwait)
+			checkFloat(t, "AvgReadLatency", got.AvgReadLatency, want.AvgReadLatency)
+			checkFloat(t, "AvgWriteLatency", got.AvgWriteLatency, want.AvgWriteLatency)
+			checkFloat(t, "AvgReadSize", got.AvgReadSize, want.AvgReadSize)
+			checkFloat(t, "AvgWriteSize", got.AvgWriteSize, want.AvgWriteSize)
+			checkFloat(t, "AvgDiscardSize", got.AvgDiscardSize, want.AvgDiscardSize)
+			checkFloat(t, "DiscardIOPS", got.DiscardIOPS, want.DiscardIOPS)
+			checkFloat(t, "FlushIOPS", got.FlushIOPS, want.FlushIOPS)
+		})
+	}
+}
+
+// findDiskstats 从diskstats内容中取出指定设备的统计
+func findDiskstats(t *testing.T, data, device string) DiskIOStats {
+	t.Helper()
+	for _, stat := range parseDiskstats(data) {
+		if stat.Device == device {
+			return stat
+		}
+	}
+	t.Fatalf("device %s not found", device)
+	return DiskIOStats{}
+}
+
+func checkUint(t *testing.T, field string, got, want uint64) {
+	t.Helper()
+	if got != want {
+		t.Errorf("%s = %d, want %d", field, got, want)
+	}
+}
+
+func checkFloat(t *testing.T, field string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", field, got, want)
+	}
+}
+
+func TestSmartctlHealth(t *testing.T) {
+	defer func(path string) { SmartctlPath = path }(SmartctlPath)
+	SmartctlPath = "testdata/smartctl/smartctl"
+
+	tests := []struct {
+		device string
+		want   DiskHealth
+	}{
+		{
+			device: "nvme0n1",
+			want: DiskHealth{
+				Model:             "Samsung SSD 980 PRO 1TB",
+				Serial:            "S5GXNX0T123456A",
+				Firmware:          "5B2QGXA7",
+				Interface:         "NVMe",
+				Capacity:          1000204886016,
+				Temperature:       41,
+				PowerOnHours:      8760,
+				PowerCycles:       512,
+				TotalBytesWritten: 41234567 * 512000,
+				TotalBytesRead:    24637123 * 512000,
+				HealthPercentage:  97,
+				RemainingLife:     97,
+			},
+		},
+		{
+			// smartctl退出码为4（部分SMART命令失败）时仍解析输出
+			device: "sda",
+			want: DiskHealth{
+				Model:             "CT500MX500SSD1",
+				Serial:            "2049E4C1A2B3",
+				Firmware:          "M3CR033",
+				Interface:         "ATA",
+				Capacity:          500107862016,
+				Temperature:       36,
+				PowerOnHours:      15000,
+				PowerCycles:       230,
+				TotalBytesWritten: 45678901234 * 512,
+				TotalBytesRead:    12345678901 * 512,
+				WearLevelingCount: 125,
+				HealthPercentage:  92,
+				RemainingLife:     92,
+			},
+		},
+		{
+			// 即将故障的机械硬盘: 没有磨损指标，SMART自检未通过
+			device: "sdb",
+			want: DiskHealth{
+				Model:            "ST2000DM008-2FR102",
+				Serial:           "ZFL1ABCD",
+				Firmware:         "0001",
+				Interface:        "ATA",
+				Capacity:         2000398934016,
+				Temperature:      38,
+				PowerOnHours:     35123,
+				PowerCycles:      1021,
+				HealthPercentage: 0,
+				RemainingLife:    100,
+				CriticalWarning:  true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.device, func(t *testing.T) {
+			health := &DiskHealth{Device: test.device, HealthPercentage: 100, RemainingLife: 100}
+			if err := getSmartctlHealth(context.Background(), "/dev/"+test.device, health); err != nil {
+				t.Fatal(err)
+			}
+
+			test.want.Device = test.device
+			if *health != test.want {
+				t.Errorf("got  %+v\nwant %+v", *health, test.want)
+			}
+		})
+	}
+
+	if err := getSmartctlHealth(context.Background(), "/dev/sdc", &DiskHealth{}); err == nil {
+		t.Error("expected error for device that cannot be opened")
+	}
+
+	// 待机中的磁盘跳过SMART，保留sysfs信息
+	for _, device := range []string{"sdd", "sde"} {
+		health := &DiskHealth{Device: device, Model: "WDC WD40EFRX-68N32N0", HealthPercentage: 100, RemainingLife: 100}
+		want := *health
+		if err := getSmartctlHealth(context.Background(), "/dev/"+device, health); err != nil {
+			t.Errorf("%s: unexpected error for standby disk: %v", device, err)
+		}
+		if *health != want {
+			t.Errorf("%s: standby disk modified: %+v", device, *health)
+		}
+	}
+}
+
+func TestParseSmartctlATAAttributes(t *testing.T) {
+	// 没有设备统计日志的旧SSD，写入量以32MiB为单位，剩余寿命取归一化值
+	data := `{
+  "device": {"protocol": "ATA"},
+  "smart_status": {"passed": true},
+  "ata_smart_attributes": {"table": [
+    {"id": 177, "name": "Wear_Leveling_Count", "value": 85, "raw": {"value": 402}},
+    {"id": 181, "name": "Program_Fail_Cnt_Total", "value": 100, "raw": {"value": 2}},
+    {"id": 182, "name": "Erase_Fail_Count_Total", "value": 100, "raw": {"value": 1}},
+    {"id": 241, "name": "Host_Writes_32MiB", "value": 100, "raw": {"value": 1000}}
+  ]}
+}`
+
+	var health DiskHealth
+	if err := parseSmartctlJSON([]byte(data), &health); err != nil {
+		t.Fatal(err)
+	}
+
+	checkUint(t, "WearLevelingCount", health.WearLevelingCount, 402)
+	checkUint(t, "ProgramFailCount", health.ProgramFailCount, 2)
+	checkUint(t, "EraseFailCount", health.EraseFailCount, 1)
+	checkUint(t, "TotalBytesWritten", health.TotalBytesWritten, 1000*32<<20)
+	checkFloat(t, "RemainingLife", health.RemainingLife, 85)
+	checkFloat(t, "HealthPercentage", health.HealthPercentage, 85)
+}
+
+func TestNewLinuxPartitionInfo(t *testing.T) {
+	for disk, want := range map[string]string{"sda": "sda3", "nvme0n1": "nvme0n1p3", "mmcblk0": "mmcblk0p3"} {
+		if got := partitionDeviceName(disk, 3); got != want {
+			t.Errorf("partitionDeviceName(%s) = %s, want %s", disk, got, want)
+		}
+	}
+
+	table := &PartitionTable{Type: PartitionTableGPT}
+	mounts := []procfs.MountInfo{
+		{Major: 259, Minor: 1, Root: "/", Mountpoint: "/boot/efi", Options: "rw,relatime", FSType: "vfat", Source: "/dev/nvme9n1p1"},
+		{Major: 259, Minor: 2, Root: "/", Mountpoint: "/srv", Options: "rw,noatime", FSType: "xfs", Source: "/dev/nvme9n1p2"},
+	}
+	swaps := map[string]bool{"/dev/nvme9n1p3": true}
+
+	efi := newLinuxPartitionInfo("nvme9n1", "nvme9n1p1", table, PartitionEntry{Number: 1, Type: gptTypeEFISystem, TypeName: "EFI System", Bootable: true}, mounts, swaps)
+	if efi.Mountpoint != "/boot/efi" || efi.FileSystem != "vfat" || !efi.IsSystem || !efi.IsBootable || efi.Disk != "/dev/nvme9n1" {
+		t.Errorf("unexpected EFI partition: %+v", efi)
+	}
+
+	data := newLinuxPartitionInfo("nvme9n1", "nvme9n1p2", table, PartitionEntry{Number: 2, Type: "0fc63daf-8483-4772-8e79-3d69d8477de4", TypeName: "Linux filesystem"}, mounts, swaps)
+	if data.Mountpoint != "/srv" || data.Options != "rw,noatime" || data.IsSystem || data.PartitionType != "Linux filesystem" {
+		t.Errorf("unexpected data partition: %+v", data)
+	}
+
+	swap := newLinuxPartitionInfo("nvme9n1", "nvme9n1p3", table, PartitionEntry{Number: 3, Type: "0657fd6d-a4ab-43c4-84e5-0933c84b4f4f", TypeName: "Linux swap"}, mounts, swaps)
+	if swap.Mountpoint != "[SWAP]" || swap.FileSystem != "swap" || !swap.IsSystem {
+		t.Errorf("unexpected swap partition: %+v", swap)
+	}
+}
+
+func TestFilterMounts(t *testing.T) {
+	mounts := []procfs.MountInfo{
+		{Major: 0, Minor: 22, Root: "/", Mountpoint: "/proc", FSType: "proc", Source: "proc"},
+		{Major: 0, Minor: 30, Root: "/", Mountpoint: "/", FSType: "overlay", Source: "overlay"},
+		{Major: 0, Minor: 25, Root: "/", Mountpoint: "/run", FSType: "tmpfs", Source: "tmpfs"},
+		// 同一设备: 先出现的bind mount挂载的是子目录
+		{Major: 8, Minor: 1, Root: "/data/containers", Mountpoint: "/var/lib/containers", FSType: "ext4", Source: "/dev/sda1"},
+		{Major: 8, Minor: 1, Root: "/", Mountpoint: "/mnt/data", FSType: "ext4", Source: "/dev/sda1"},
+		{Major: 8, Minor: 1, Root: "/", Mountpoint: "/data", FSType: "ext4", Source: "/dev/sda1"},
+		// 同一设备的两个子目录挂载，保留挂载点最短的
+		{Major: 8, Minor: 2, Root: "/@home", Mountpoint: "/home/user", FSType: "btrfs", Source: "/dev/sda2"},
+		{Major: 8, Minor: 2, Root: "/@log", Mountpoint: "/var/log", FSType: "btrfs", Source: "/dev/sda2"},
+		{Major: 0, Minor: 50, Root: "/export", Mountpoint: "/mnt/nfs", FSType: "nfs4", Source: "server:/export"},
+		{Major: 0, Minor: 51, Root: "/", Mountpoint: "/mnt/remote", FSType: "fuse.sshfs", Source: "user@host:/"},
+	}
+
+	tests := []struct {
+		name    string
+		options DiskOptions
+		want    []string
+	}{
+		{"default", DiskOptions{}, []string{"/", "/data", "/var/log"}},
+		{"network", DiskOptions{IncludeNetwork: true}, []string{"/", "/data", "/var/log", "/mnt/nfs", "/mnt/remote"}},
+		{"pseudo", DiskOptions{IncludePseudo: true}, []string{"/proc", "/", "/run", "/data", "/var/log"}},
+		{"bind mounts", DiskOptions{IncludeBindMounts: true}, []string{"/", "/var/lib/containers", "/mnt/data", "/data", "/home/user", "/var/log"}},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, mount := range filterMounts(mounts, test.options) {
+			got = append(got, mount.Mountpoint)
+		}
+		if strings.Join(got, " ") != strings.Join(test.want, " ") {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestStatfsWithTimeoutSkipsInFlight(t *testing.T) {
+	path := t.TempDir()
+
+	var stat syscall.Statfs_t
+	if err := statfsWithTimeout(path, &stat, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if stat.Blocks == 0 {
+		t.Error("expected statfs result")
+	}
+
+	// 模拟上次调用仍阻塞在内核中
+	statfsInFlightMu.Lock()
+	statfsInFlight[path] = true
+	statfsInFlightMu.Unlock()
+	defer func() {
+		statfsInFlightMu.Lock()
+		delete(statfsInFlight, path)
+		statfsInFlightMu.Unlock()
+	}()
+
+	if err := statfsWithTimeout(path, &stat, time.Second); err == nil {
+		t.Error("expected in-flight mount to be skipped")
+	}
+}
+
+func TestReadSysfsPartitions(t *testing.T) {
+	disk := filepath.Join(t.TempDir(), "nvme0n1")
+	write := func(name string, files map[string]string) {
+		dir := filepath.Join(disk, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for file, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write("nvme0n1p10", map[string]string{"partition": "10", "start": "8390656", "size": "2048", "dev": "259:10"})
+	write("nvme0n1p1", map[string]string{"partition": "1", "start": "2048", "size": "1048576", "dev": "259:1"})
+	write("nvme0n1p2", map[string]string{"partition": "2", "start": "1050624", "size": "7340032", "dev": "259:2"})
+	// 磁盘目录下的其他子目录没有partition文件
+	write("queue", map[string]string{"logical_block_size": "512"})
+	write("power", map[string]string{"runtime_status": "active"})
+
+	partitions := readSysfsPartitions(disk)
+	want := []sysfsPartition{
+		{name: "nvme0n1p1", dev: "259:1", number: 1, start: 2048 * 512, size: 1048576 * 512},
+		{name: "nvme0n1p2", dev: "259:2", number: 2, start: 1050624 * 512, size: 7340032 * 512},
+		{name: "nvme0n1p10", dev: "259:10", number: 10, start: 8390656 * 512, size: 2048 * 512},
+	}
+	if len(partitions) != len(want) {
+		t.Fatalf("got %+v, want %+v", partitions, want)
+	}
+	for i := range want {
+		if partitions[i] != want[i] {
+			t.Errorf("partition %d: got %+v, want %+v", i, partitions[i], want[i])
+		}
+	}
+}
+
+func TestUdevPartitionEntry(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name      string
+		data      string
+		tableType string
+		want      PartitionEntry
+	}{
+		{
+			name: "gpt efi",
+			data: "S:disk/by-partuuid/5f1e9b2a-0000-4c1e-8a77-3f0e2b9d1c01\nW:12\nI:3456789\n" +
+				"E:ID_PART_ENTRY_SCHEME=gpt\nE:ID_PART_ENTRY_NAME=EFI\\x20System\\x20Partition\n" +
+				"E:ID_PART_ENTRY_UUID=5f1e9b2a-0000-4c1e-8a77-3f0e2b9d1c01\n" +
+				"E:ID_PART_ENTRY_TYPE=C12A7328-F81F-11D2-BA4B-00A0C93EC93B\nE:ID_PART_ENTRY_NUMBER=1\n" +
+				"G:systemd\n",
+			tableType: PartitionTableGPT,
+			want: PartitionEntry{Type: gptTypeEFISystem, TypeName: "EFI System", UUID: "5f1e9b2a-0000-4c1e-8a77-3f0e2b9d1c01",
+				Name: "EFI System Partition", Bootable: true},
+		},
+		{
+			name: "gpt legacy bootable",
+			data: "E:ID_PART_ENTRY_SCHEME=gpt\nE:ID_PART_ENTRY_NAME=root\nE:ID_PART_ENTRY_UUID=0b6a1f3c-54d2-4c1e-8a77-3f0e2b9d1c02\n" +
+				"E:ID_PART_ENTRY_TYPE=0fc63daf-8483-4772-8e79-3d69d8477de4\nE:ID_PART_ENTRY_FLAGS=0x4\n",
+			tableType: PartitionTableGPT,
+			want: PartitionEntry{Type: "0fc63daf-8483-4772-8e79-3d69d8477de4", TypeName: "Linux filesystem",
+				UUID: "0b6a1f3c-54d2-4c1e-8a77-3f0e2b9d1c02", Name: "root", Bootable: true},
+		},
+		{
+			name:      "dos active",
+			data:      "E:ID_PART_ENTRY_SCHEME=dos\nE:ID_PART_ENTRY_UUID=1a2b3c4d-01\nE:ID_PART_ENTRY_TYPE=0x83\nE:ID_PART_ENTRY_FLAGS=0x80\n",
+			tableType: PartitionTableMBR,
+			want:      PartitionEntry{Type: "0x83", TypeName: "Linux", UUID: "1a2b3c4d-01", Bootable: true},
+		},
+		{
+			name:      "dos extended",
+			data:      "E:ID_PART_ENTRY_SCHEME=dos\nE:ID_PART_ENTRY_UUID=1a2b3c4d-02\nE:ID_PART_ENTRY_TYPE=0x5\n",
+			tableType: PartitionTableMBR,
+			want:      PartitionEntry{Type: "0x05", TypeName: "Extended", UUID: "1a2b3c4d-02"},
+		},
+		{
+			// udev尚未探测或没有blkid信息
+			name: "no partition entry",
+			data: "E:ID_SERIAL=QEMU_HARDDISK\nE:DEVTYPE=partition\n",
+		},
+	}
+
+	for i, test := range tests {
+		props, err := readUdevProperties(write(fmt.Sprintf("b8:%d", i), test.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var entry PartitionEntry
+		tableType, ok := udevPartitionEntry(&entry, props)
+		if ok != (test.tableType != "") || tableType != test.tableType {
+			t.Errorf("%s: table type = %q/%v, want %q", test.name, tableType, ok, test.tableType)
+			continue
+		}
+		if ok && entry != test.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", test.name, entry, test.want)
+		}
+	}
+
+	if _, err := readUdevProperties(filepath.Join(dir, "b8:99")); err == nil {
+		t.Error("expected error for missing udev data")
+	}
+}
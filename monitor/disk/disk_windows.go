@@ -7,7 +7,7 @@ import (
 )
 
 // getPlatformDisks 获取平台磁盘信息
-func getPlatformDisks() ([]DiskInfo, error) {
+func getPlatformDisks(options DiskOptions) ([]DiskInfo, error) {
 	return nil, fmt.Errorf("Windows disk info not implemented yet")
 }
 
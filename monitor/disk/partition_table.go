@@ -0,0 +1,384 @@
+package disk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// 分区表类型
+const (
+	PartitionTableGPT = "gpt" // GUID分区表
+	PartitionTableMBR = "mbr" // 主引导记录 (DOS分区表)
+)
+
+// PartitionTable 从磁盘或镜像文件读取的分区表
+type PartitionTable struct {
+	Type       string           `json:"type"`        // 分区表类型: gpt, mbr
+	DiskID     string           `json:"disk_id"`     // GPT磁盘GUID或MBR磁盘签名
+	SectorSize uint64           `json:"sector_size"` // 扇区大小 (bytes)
+	Partitions []PartitionEntry `json:"partitions"`  // 分区列表
+}
+
+// PartitionEntry 分区表中的一个分区
+type PartitionEntry struct {
+	Number   int    `json:"number"`    // 分区号，与内核编号一致: GPT为表项序号，MBR主分区1-4、逻辑分区从5开始
+	Start    uint64 `json:"start"`     // 起始偏移 (bytes)
+	Size     uint64 `json:"size"`      // 大小 (bytes)
+	Type     string `json:"type"`      // GPT类型GUID或MBR类型码 (如0x83)
+	TypeName string `json:"type_name"` // 类型名称，未知类型为空
+	UUID     string `json:"uuid"`      // 分区唯一标识 (PARTUUID)，MBR为"磁盘签名-分区号"
+	Name     string `json:"name"`      // GPT分区名
+	Bootable bool   `json:"bootable"`  // MBR活动分区、GPT传统BIOS可启动属性或EFI系统分区
+}
+
+// mbrSectorSize MBR中的LBA按512字节扇区计算
+const mbrSectorSize = 512
+
+// GPT头部与分区项的合理范围，防止损坏的头部导致超大读取
+const (
+	gptHeaderMinSize   = 92
+	gptMaxEntries      = 1024
+	gptMinEntrySize    = 128
+	gptMaxEntryBytes   = 1 << 20
+	gptBIOSBootableBit = 1 << 2
+)
+
+// MBR分区类型码
+const (
+	mbrTypeEmpty         = 0x00
+	mbrTypeExtendedCHS   = 0x05
+	mbrTypeExtendedLBA   = 0x0f
+	mbrTypeLinuxExtended = 0x85
+	mbrTypeEFI           = 0xef
+	mbrTypeGPTProtective = 0xee
+)
+
+// gptTypeEFISystem EFI系统分区类型GUID
+const gptTypeEFISystem = "c12a7328-f81f-11d2-ba4b-00a0c93ec93b"
+
+// gptPartitionTypes 常见GPT分区类型GUID
+var gptPartitionTypes = map[string]string{
+	gptTypeEFISystem:                       "EFI System",
+	"21686148-6449-6e6f-744e-656564454649": "BIOS boot",
+	"0fc63daf-8483-4772-8e79-3d69d8477de4": "Linux filesystem",
+	"0657fd6d-a4ab-43c4-84e5-0933c84b4f4f": "Linux swap",
+	"e6d6d379-f507-44c2-a23c-238f2a3df928": "Linux LVM",
+	"a19d880f-05fc-4d3b-a006-743f0f84911e": "Linux RAID",
+	"4f68bce3-e8cd-4db1-96e7-fbcaf984b709": "Linux root (x86-64)",
+	"b921b045-1df0-41c3-af44-4c6f280d3fae": "Linux root (ARM-64)",
+	"bc13c2ff-59e6-4262-a352-b275fd6f7172": "Linux extended boot",
+	"933ac7e1-2eb4-4f13-b844-0e14e2aef915": "Linux home",
+	"ca7d7ccb-63ed-4c53-861c-1742536059cc": "Linux LUKS",
+	"ebd0a0a2-b9e5-4433-87c0-68b6b72699c7": "Microsoft basic data",
+	"e3c9e316-0b5c-4db8-817d-f92df00215ae": "Microsoft reserved",
+	"de94bba4-06d1-4d40-a16a-bfd50179d6ac": "Windows recovery environment",
+	"48465300-0000-11aa-aa11-00306543ecac": "Apple HFS/HFS+",
+	"7c3457ef-0000-11aa-aa11-00306543ecac": "Apple APFS",
+}
+
+// mbrPartitionTypes 常见MBR分区类型码
+var mbrPartitionTypes = map[byte]string{
+	0x01:                 "FAT12",
+	0x06:                 "FAT16",
+	mbrTypeExtendedCHS:   "Extended",
+	0x07:                 "HPFS/NTFS/exFAT",
+	0x0b:                 "W95 FAT32",
+	0x0c:                 "W95 FAT32 (LBA)",
+	mbrTypeExtendedLBA:   "W95 Extended (LBA)",
+	0x82:                 "Linux swap",
+	0x83:                 "Linux",
+	mbrTypeLinuxExtended: "Linux extended",
+	0x8e:                 "Linux LVM",
+	0xa5:                 "FreeBSD",
+	mbrTypeGPTProtective: "GPT protective",
+	mbrTypeEFI:           "EFI System",
+	0xfd:                 "Linux raid autodetect",
+}
+
+// mbrEntry MBR分区表项
+type mbrEntry struct {
+	bootable bool
+	partType byte
+	startLBA uint64
+	sectors  uint64
+}
+
+// ReadPartitionTable 读取块设备或磁盘镜像文件的GPT/MBR分区表
+// GPT主头部损坏时使用备份头部；MBR的扩展分区会沿EBR链读取逻辑分区
+func ReadPartitionTable(path string) (*PartitionTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// 块设备Stat().Size()为0，通过Seek获取大小
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get size of %s: %v", path, err)
+	}
+
+	table, err := parsePartitionTable(file, uint64(size))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return table, nil
+}
+
+// parsePartitionTable 解析分区表，存在保护性MBR或没有MBR时优先识别GPT
+func parsePartitionTable(r io.ReaderAt, size uint64) (*PartitionTable, error) {
+	mbr := make([]byte, mbrSectorSize)
+	if _, err := r.ReadAt(mbr, 0); err != nil {
+		return nil, fmt.Errorf("failed to read MBR: %v", err)
+	}
+
+	hasMBR := mbr[510] == 0x55 && mbr[511] == 0xaa
+	protective := false
+	if hasMBR {
+		for _, entry := range parseMBREntries(mbr) {
+			if entry.partType == mbrTypeGPTProtective {
+				protective = true
+			}
+		}
+	}
+
+	// 没有保护性MBR时，残留的GPT头部不可信（如磁盘被重新用MBR分区）
+	if protective || !hasMBR {
+		var gptErr error
+		for _, sectorSize := range []uint64{512, 4096} {
+			table, err := parseGPT(r, size, sectorSize)
+			if err == nil {
+				return table, nil
+			}
+			if gptErr == nil {
+				gptErr = err
+			}
+		}
+		if protective {
+			return nil, gptErr
+		}
+	}
+
+	if !hasMBR {
+		return nil, fmt.Errorf("no partition table found")
+	}
+	return parseMBR(r, mbr)
+}
+
+// parseGPT 按指定扇区大小解析GPT，主头部或分区项校验失败时尝试磁盘末尾的备份头部
+func parseGPT(r io.ReaderAt, size, sectorSize uint64) (*PartitionTable, error) {
+	table, err := readGPT(r, 1, sectorSize)
+	if err == nil {
+		return table, nil
+	}
+
+	if size >= 2*sectorSize {
+		if backup, backupErr := readGPT(r, size/sectorSize-1, sectorSize); backupErr == nil {
+			return backup, nil
+		}
+	}
+	return nil, err
+}
+
+// readGPT 读取指定LBA处的GPT头部及其分区项
+// 头部布局: 0 签名"EFI PART", 12 头部大小, 16 头部CRC32, 24 本头部LBA, 56 磁盘GUID,
+// 72 分区项起始LBA, 80 分区项数量, 84 分区项大小, 88 分区项CRC32
+func readGPT(r io.ReaderAt, lba, sectorSize uint64) (*PartitionTable, error) {
+	header := make([]byte, sectorSize)
+	if _, err := r.ReadAt(header, int64(lba*sectorSize)); err != nil {
+		return nil, fmt.Errorf("failed to read GPT header: %v", err)
+	}
+	if string(header[0:8]) != "EFI PART" {
+		return nil, fmt.Errorf("GPT signature not found at LBA %d", lba)
+	}
+
+	headerSize := uint64(binary.LittleEndian.Uint32(header[12:16]))
+	if headerSize < gptHeaderMinSize || headerSize > sectorSize {
+		return nil, fmt.Errorf("invalid GPT header size %d", headerSize)
+	}
+	checksum := binary.LittleEndian.Uint32(header[16:20])
+	binary.LittleEndian.PutUint32(header[16:20], 0)
+	if crc32.ChecksumIEEE(header[:headerSize]) != checksum {
+		return nil, fmt.Errorf("GPT header checksum mismatch at LBA %d", lba)
+	}
+	if binary.LittleEndian.Uint64(header[24:32]) != lba {
+		return nil, fmt.Errorf("GPT header at LBA %d has wrong location", lba)
+	}
+
+	entriesLBA := binary.LittleEndian.Uint64(header[72:80])
+	numEntries := uint64(binary.LittleEndian.Uint32(header[80:84]))
+	entrySize := uint64(binary.LittleEndian.Uint32(header[84:88]))
+	if numEntries > gptMaxEntries || entrySize < gptMinEntrySize || entrySize%8 != 0 || numEntries*entrySize > gptMaxEntryBytes {
+		return nil, fmt.Errorf("invalid GPT partition array (%d entries of %d bytes)", numEntries, entrySize)
+	}
+
+	entries := make([]byte, numEntries*entrySize)
+	if _, err := r.ReadAt(entries, int64(entriesLBA*sectorSize)); err != nil {
+		return nil, fmt.Errorf("failed to read GPT partition entries: %v", err)
+	}
+	if crc32.ChecksumIEEE(entries) != binary.LittleEndian.Uint32(header[88:92]) {
+		return nil, fmt.Errorf("GPT partition entries checksum mismatch")
+	}
+
+	table := &PartitionTable{
+		Type:       PartitionTableGPT,
+		DiskID:     formatGUID(header[56:72]),
+		SectorSize: sectorSize,
+	}
+
+	// 分区项布局: 0 类型GUID, 16 分区GUID, 32 起始LBA, 40 结束LBA (含), 48 属性, 56 UTF-16LE名称 (72字节)
+	for i := uint64(0); i < numEntries; i++ {
+		entry := entries[i*entrySize : (i+1)*entrySize]
+		if bytes.Equal(entry[0:16], make([]byte, 16)) {
+			continue
+		}
+
+		firstLBA := binary.LittleEndian.Uint64(entry[32:40])
+		lastLBA := binary.LittleEndian.Uint64(entry[40:48])
+		if lastLBA < firstLBA {
+			continue
+		}
+
+		typeGUID := formatGUID(entry[0:16])
+		attributes := binary.LittleEndian.Uint64(entry[48:56])
+		table.Partitions = append(table.Partitions, PartitionEntry{
+			Number:   int(i) + 1,
+			Start:    firstLBA * sectorSize,
+			Size:     (lastLBA - firstLBA + 1) * sectorSize,
+			Type:     typeGUID,
+			TypeName: gptPartitionTypes[typeGUID],
+			UUID:     formatGUID(entry[16:32]),
+			Name:     decodeUTF16Name(entry[56:128]),
+			Bootable: attributes&gptBIOSBootableBit != 0 || typeGUID == gptTypeEFISystem,
+		})
+	}
+
+	return table, nil
+}
+
+// parseMBR 解析MBR主分区与扩展分区中的逻辑分区
+func parseMBR(r io.ReaderAt, mbr []byte) (*PartitionTable, error) {
+	signature := binary.LittleEndian.Uint32(mbr[440:444])
+	table := &PartitionTable{
+		Type:       PartitionTableMBR,
+		DiskID:     fmt.Sprintf("%08x", signature),
+		SectorSize: mbrSectorSize,
+	}
+
+	var extended *mbrEntry
+	for i, entry := range parseMBREntries(mbr) {
+		if entry.partType == mbrTypeEmpty || entry.sectors == 0 {
+			continue
+		}
+		if isExtendedPartition(entry.partType) && extended == nil {
+			extended = &entry
+		}
+		table.Partitions = append(table.Partitions, newMBRPartition(i+1, signature, entry, 0))
+	}
+
+	if extended != nil {
+		logical, err := readLogicalPartitions(r, signature, extended.startLBA)
+		if err != nil {
+			return nil, err
+		}
+		table.Partitions = append(table.Partitions, logical...)
+	}
+
+	return table, nil
+}
+
+// readLogicalPartitions 沿EBR链读取逻辑分区
+// 每个EBR的第一项是相对本EBR的逻辑分区，第二项是相对扩展分区起点的下一个EBR
+func readLogicalPartitions(r io.ReaderAt, signature uint32, extendedStart uint64) ([]PartitionEntry, error) {
+	var partitions []PartitionEntry
+	visited := make(map[uint64]bool)
+	ebrLBA := extendedStart
+	number := 5
+
+	for !visited[ebrLBA] && len(visited) < gptMaxEntries {
+		visited[ebrLBA] = true
+
+		ebr := make([]byte, mbrSectorSize)
+		if _, err := r.ReadAt(ebr, int64(ebrLBA*mbrSectorSize)); err != nil {
+			return nil, fmt.Errorf("failed to read EBR at LBA %d: %v", ebrLBA, err)
+		}
+		if ebr[510] != 0x55 || ebr[511] != 0xaa {
+			break
+		}
+
+		entries := parseMBREntries(ebr)
+		if entries[0].partType != mbrTypeEmpty && entries[0].sectors > 0 {
+			partitions = append(partitions, newMBRPartition(number, signature, entries[0], ebrLBA))
+			number++
+		}
+
+		if !isExtendedPartition(entries[1].partType) || entries[1].sectors == 0 {
+			break
+		}
+		ebrLBA = extendedStart + entries[1].startLBA
+	}
+
+	return partitions, nil
+}
+
+// parseMBREntries 解析从偏移446开始的4个16字节分区表项
+// 表项布局: 0 引导标志(0x80), 4 类型码, 8 起始LBA, 12 扇区数
+func parseMBREntries(sector []byte) [4]mbrEntry {
+	var entries [4]mbrEntry
+	for i := range entries {
+		entry := sector[446+i*16 : 446+(i+1)*16]
+		entries[i] = mbrEntry{
+			bootable: entry[0] == 0x80,
+			partType: entry[4],
+			startLBA: uint64(binary.LittleEndian.Uint32(entry[8:12])),
+			sectors:  uint64(binary.LittleEndian.Uint32(entry[12:16])),
+		}
+	}
+	return entries
+}
+
+// newMBRPartition 构造MBR分区，base为起始LBA的基准（逻辑分区为所在EBR的LBA）
+func newMBRPartition(number int, signature uint32, entry mbrEntry, base uint64) PartitionEntry {
+	return PartitionEntry{
+		Number:   number,
+		Start:    (base + entry.startLBA) * mbrSectorSize,
+		Size:     entry.sectors * mbrSectorSize,
+		Type:     fmt.Sprintf("0x%02x", entry.partType),
+		TypeName: mbrPartitionTypes[entry.partType],
+		UUID:     fmt.Sprintf("%08x-%02x", signature, number),
+		Bootable: entry.bootable,
+	}
+}
+
+// isExtendedPartition 判断MBR类型码是否为扩展分区
+func isExtendedPartition(partType byte) bool {
+	return partType == mbrTypeExtendedCHS || partType == mbrTypeExtendedLBA || partType == mbrTypeLinuxExtended
+}
+
+// formatGUID 将GPT中的混合字节序GUID格式化为小写字符串，前三段为小端序
+func formatGUID(b []byte) string {
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(b[0:4]),
+		binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]),
+		b[8:10], b[10:16])
+}
+
+// decodeUTF16Name 解码GPT分区名 (UTF-16LE，以0结尾)
+func decodeUTF16Name(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		unit := binary.LittleEndian.Uint16(b[i : i+2])
+		if unit == 0 {
+			break
+		}
+		units = append(units, unit)
+	}
+	return strings.TrimSpace(string(utf16.Decode(units)))
+}
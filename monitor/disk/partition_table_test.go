@@ -0,0 +1,242 @@
+package disk
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// testGPTPartition 生成GPT镜像用的分区描述
+type testGPTPartition struct {
+	typeGUID   string
+	uuid       string
+	name       string
+	firstLBA   uint64
+	lastLBA    uint64
+	attributes uint64
+}
+
+const testDiskGUID = "5c2a6f0e-7b1d-4e3a-9f44-2d1c8b7e6a01"
+
+// encodeGUID 将GUID字符串编码为GPT中的混合字节序
+func encodeGUID(t *testing.T, guid string) []byte {
+	t.Helper()
+	raw, err := hex.DecodeString(strings.ReplaceAll(guid, "-", ""))
+	if err != nil || len(raw) != 16 {
+		t.Fatalf("invalid GUID %q", guid)
+	}
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint32(b[0:4], binary.BigEndian.Uint32(raw[0:4]))
+	binary.LittleEndian.PutUint16(b[4:6], binary.BigEndian.Uint16(raw[4:6]))
+	binary.LittleEndian.PutUint16(b[6:8], binary.BigEndian.Uint16(raw[6:8]))
+	copy(b[8:], raw[8:])
+	return b
+}
+
+// writeGPTImage 生成带保护性MBR、主/备份GPT的磁盘镜像
+func writeGPTImage(t *testing.T, sectorSize, sectors uint64, partitions []testGPTPartition) string {
+	t.Helper()
+	const numEntries, entrySize = 128, 128
+
+	image := make([]byte, sectorSize*sectors)
+	entryLBAs := numEntries * entrySize / sectorSize
+
+	// 保护性MBR
+	image[446+4] = mbrTypeGPTProtective
+	binary.LittleEndian.PutUint32(image[446+8:], 1)
+	binary.LittleEndian.PutUint32(image[446+12:], uint32(sectors-1))
+	image[510], image[511] = 0x55, 0xaa
+
+	entries := make([]byte, numEntries*entrySize)
+	for i, partition := range partitions {
+		entry := entries[i*entrySize:]
+		copy(entry[0:16], encodeGUID(t, partition.typeGUID))
+		copy(entry[16:32], encodeGUID(t, partition.uuid))
+		binary.LittleEndian.PutUint64(entry[32:40], partition.firstLBA)
+		binary.LittleEndian.PutUint64(entry[40:48], partition.lastLBA)
+		binary.LittleEndian.PutUint64(entry[48:56], partition.attributes)
+		for j, unit := range utf16.Encode([]rune(partition.name)) {
+			binary.LittleEndian.PutUint16(entry[56+j*2:], unit)
+		}
+	}
+
+	writeHeader := func(lba, alternateLBA, entriesLBA uint64) {
+		header := image[lba*sectorSize : lba*sectorSize+gptHeaderMinSize]
+		copy(header[0:8], "EFI PART")
+		binary.LittleEndian.PutUint32(header[8:12], 0x00010000)
+		binary.LittleEndian.PutUint32(header[12:16], gptHeaderMinSize)
+		binary.LittleEndian.PutUint64(header[24:32], lba)
+		binary.LittleEndian.PutUint64(header[32:40], alternateLBA)
+		binary.LittleEndian.PutUint64(header[40:48], 2+entryLBAs)
+		binary.LittleEndian.PutUint64(header[48:56], sectors-2-entryLBAs)
+		copy(header[56:72], encodeGUID(t, testDiskGUID))
+		binary.LittleEndian.PutUint64(header[72:80], entriesLBA)
+		binary.LittleEndian.PutUint32(header[80:84], numEntries)
+		binary.LittleEndian.PutUint32(header[84:88], entrySize)
+		binary.LittleEndian.PutUint32(header[88:92], crc32.ChecksumIEEE(entries))
+		binary.LittleEndian.PutUint32(header[16:20], crc32.ChecksumIEEE(header))
+		copy(image[entriesLBA*sectorSize:], entries)
+	}
+	writeHeader(1, sectors-1, 2)
+	writeHeader(sectors-1, 1, sectors-1-entryLBAs)
+
+	return writeImage(t, image)
+}
+
+// writeImage 将镜像写入临时文件
+func writeImage(t *testing.T, image []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, image, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// putMBREntry 写入MBR/EBR分区表项
+func putMBREntry(sector []byte, index int, bootable bool, partType byte, startLBA, sectors uint32) {
+	entry := sector[446+index*16:]
+	if bootable {
+		entry[0] = 0x80
+	}
+	entry[4] = partType
+	binary.LittleEndian.PutUint32(entry[8:12], startLBA)
+	binary.LittleEndian.PutUint32(entry[12:16], sectors)
+	sector[510], sector[511] = 0x55, 0xaa
+}
+
+var testGPTPartitions = []testGPTPartition{
+	{typeGUID: gptTypeEFISystem, uuid: "0b6a1f3c-54d2-4c1e-8a77-3f0e2b9d1c01", name: "EFI System Partition", firstLBA: 2048, lastLBA: 4095},
+	{typeGUID: "0fc63daf-8483-4772-8e79-3d69d8477de4", uuid: "0b6a1f3c-54d2-4c1e-8a77-3f0e2b9d1c02", name: "root", firstLBA: 4096, lastLBA: 8191, attributes: gptBIOSBootableBit},
+	{typeGUID: "0657fd6d-a4ab-43c4-84e5-0933c84b4f4f", uuid: "0b6a1f3c-54d2-4c1e-8a77-3f0e2b9d1c03", name: "swap", firstLBA: 8192, lastLBA: 10239},
+	{typeGUID: "e6d6d379-f507-44c2-a23c-238f2a3df928", uuid: "0b6a1f3c-54d2-4c1e-8a77-3f0e2b9d1c04", name: "数据", firstLBA: 10240, lastLBA: 12287},
+}
+
+func checkGPTTable(t *testing.T, table *PartitionTable, sectorSize uint64) {
+	t.Helper()
+	if table.Type != PartitionTableGPT || table.DiskID != testDiskGUID || table.SectorSize != sectorSize {
+		t.Fatalf("unexpected table: type=%s disk=%s sector=%d", table.Type, table.DiskID, table.SectorSize)
+	}
+	if len(table.Partitions) != len(testGPTPartitions) {
+		t.Fatalf("expected %d partitions, got %d", len(testGPTPartitions), len(table.Partitions))
+	}
+
+	wantTypes := []string{"EFI System", "Linux filesystem", "Linux swap", "Linux LVM"}
+	wantBootable := []bool{true, true, false, false}
+	for i, partition := range table.Partitions {
+		want := testGPTPartitions[i]
+		if partition.Number != i+1 || partition.Type != want.typeGUID || partition.UUID != want.uuid || partition.Name != want.name {
+			t.Errorf("partition %d: got %+v", i+1, partition)
+		}
+		if partition.Start != want.firstLBA*sectorSize || partition.Size != (want.lastLBA-want.firstLBA+1)*sectorSize {
+			t.Errorf("partition %d: start=%d size=%d", i+1, partition.Start, partition.Size)
+		}
+		if partition.TypeName != wantTypes[i] || partition.Bootable != wantBootable[i] {
+			t.Errorf("partition %d: type=%q bootable=%v", i+1, partition.TypeName, partition.Bootable)
+		}
+	}
+}
+
+func TestReadPartitionTableGPT(t *testing.T) {
+	table, err := ReadPartitionTable(writeGPTImage(t, 512, 16384, testGPTPartitions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGPTTable(t, table, 512)
+}
+
+func TestReadPartitionTableGPT4Kn(t *testing.T) {
+	table, err := ReadPartitionTable(writeGPTImage(t, 4096, 16384, testGPTPartitions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGPTTable(t, table, 4096)
+}
+
+func TestReadPartitionTableGPTBackupHeader(t *testing.T) {
+	path := writeGPTImage(t, 512, 16384, testGPTPartitions)
+	image, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 破坏主头部中的分区数量，CRC校验失败后应使用备份头部
+	image[512+80]++
+	if err := os.WriteFile(path, image, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := ReadPartitionTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGPTTable(t, table, 512)
+}
+
+func TestReadPartitionTableMBR(t *testing.T) {
+	image := make([]byte, 8192*512)
+	mbr := image[:512]
+	binary.LittleEndian.PutUint32(mbr[440:444], 0x1a2b3c4d)
+	putMBREntry(mbr, 0, true, 0x83, 2048, 2048)
+	putMBREntry(mbr, 1, false, mbrTypeExtendedLBA, 4096, 4096)
+
+	// 扩展分区内两个逻辑分区: swap与LVM
+	putMBREntry(image[4096*512:], 0, false, 0x82, 1, 1023)
+	putMBREntry(image[4096*512:], 1, false, mbrTypeExtendedCHS, 1024, 2048)
+	putMBREntry(image[5120*512:], 0, false, 0x8e, 1, 2047)
+
+	table, err := ReadPartitionTable(writeImage(t, image))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Type != PartitionTableMBR || table.DiskID != "1a2b3c4d" {
+		t.Fatalf("unexpected table: %+v", table)
+	}
+
+	want := []PartitionEntry{
+		{Number: 1, Start: 2048 * 512, Size: 2048 * 512, Type: "0x83", TypeName: "Linux", UUID: "1a2b3c4d-01", Bootable: true},
+		{Number: 2, Start: 4096 * 512, Size: 4096 * 512, Type: "0x0f", TypeName: "W95 Extended (LBA)", UUID: "1a2b3c4d-02"},
+		{Number: 5, Start: 4097 * 512, Size: 1023 * 512, Type: "0x82", TypeName: "Linux swap", UUID: "1a2b3c4d-05"},
+		{Number: 6, Start: 5121 * 512, Size: 2047 * 512, Type: "0x8e", TypeName: "Linux LVM", UUID: "1a2b3c4d-06"},
+	}
+	if len(table.Partitions) != len(want) {
+		t.Fatalf("expected %d partitions, got %+v", len(want), table.Partitions)
+	}
+	for i := range want {
+		if table.Partitions[i] != want[i] {
+			t.Errorf("partition %d:\ngot  %+v\nwant %+v", i, table.Partitions[i], want[i])
+		}
+	}
+}
+
+func TestReadPartitionTableStaleGPT(t *testing.T) {
+	// 重新用MBR分区的磁盘上残留GPT头部，没有保护性MBR时应按MBR解析
+	path := writeGPTImage(t, 512, 16384, testGPTPartitions)
+	image, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(image[446:510], make([]byte, 64))
+	putMBREntry(image, 0, false, 0x83, 2048, 8192)
+	if err := os.WriteFile(path, image, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := ReadPartitionTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Type != PartitionTableMBR || len(table.Partitions) != 1 || table.Partitions[0].TypeName != "Linux" {
+		t.Errorf("unexpected table: %+v", table)
+	}
+}
+
+func TestReadPartitionTableNone(t *testing.T) {
+	if _, err := ReadPartitionTable(writeImage(t, make([]byte, 64*1024))); err == nil {
+		t.Error("expected error for image without partition table")
+	}
+}
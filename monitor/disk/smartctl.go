@@ -0,0 +1,287 @@
+package disk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SmartctlPath smartctl可执行文件路径，可替换为自定义路径或测试用脚本
+var SmartctlPath = "smartctl"
+
+// smartctl退出码位0、1表示命令行错误或设备无法打开，其余位（如SMART命令失败、磁盘即将故障）仍会输出完整JSON
+const smartctlFatalExitMask = 0x3
+
+// smartctlOpenFailedBit 退出码位1: 设备无法打开，或在-n standby下磁盘处于低功耗模式
+const smartctlOpenFailedBit = 0x2
+
+// smartctlTimeout 一次健康检查中smartctl的总超时时间，无响应的磁盘或USB桥接器可能使smartctl长时间阻塞
+const smartctlTimeout = 30 * time.Second
+
+// nvmeDataUnit NVMe data_units_read/written的单位: 1000个512字节块
+const nvmeDataUnit = 512 * 1000
+
+// smartctlOutput smartctl --json -a 输出中用到的字段
+type smartctlOutput struct {
+	Device struct {
+		Protocol string `json:"protocol"`
+	} `json:"device"`
+	ModelName        string `json:"model_name"`
+	SerialNumber     string `json:"serial_number"`
+	FirmwareVersion  string `json:"firmware_version"`
+	LogicalBlockSize uint64 `json:"logical_block_size"`
+	UserCapacity     struct {
+		Bytes uint64 `json:"bytes"`
+	} `json:"user_capacity"`
+	NVMeTotalCapacity uint64 `json:"nvme_total_capacity"`
+	SmartStatus       *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	PowerCycleCount uint64 `json:"power_cycle_count"`
+
+	NVMeHealth *struct {
+		CriticalWarning  uint64 `json:"critical_warning"` // 位掩码，包括备用块不足、温度越限、只读模式等
+		Temperature      uint64 `json:"temperature"`
+		PercentageUsed   uint64 `json:"percentage_used"`
+		DataUnitsRead    uint64 `json:"data_units_read"`
+		DataUnitsWritten uint64 `json:"data_units_written"`
+		PowerCycles      uint64 `json:"power_cycles"`
+		PowerOnHours     uint64 `json:"power_on_hours"`
+	} `json:"nvme_smart_health_information_log"`
+
+	ATAAttributes struct {
+		Table []smartctlATAAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+
+	ATAStatistics struct {
+		Pages []struct {
+			Number int `json:"number"`
+			Table  []struct {
+				Name  string `json:"name"`
+				Value uint64 `json:"value"`
+				Flags struct {
+					Valid bool `json:"valid"`
+				} `json:"flags"`
+			} `json:"table"`
+		} `json:"pages"`
+	} `json:"ata_device_statistics"`
+}
+
+// smartctlATAAttribute ATA SMART属性
+type smartctlATAAttribute struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Value      uint64 `json:"value"` // 厂商归一化值，通常100或200为全新
+	WhenFailed string `json:"when_failed"`
+	Raw        struct {
+		Value uint64 `json:"value"`
+	} `json:"raw"`
+}
+
+// ATA中表示剩余寿命的属性，归一化值即剩余寿命百分比，按可信度排序
+var ataRemainingLifeAttributes = []int{
+	231, // SSD_Life_Left
+	233, // Media_Wearout_Indicator (Intel)
+	177, // Wear_Leveling_Count (Samsung)
+	202, // Percent_Lifetime_Remain (Crucial/Micron)
+}
+
+// getSmartctlHealth 执行smartctl --json -a并将结果合并到health，ctx到期时终止smartctl
+// 使用-n standby避免唤醒待机的机械硬盘，磁盘处于待机时跳过SMART，health保持不变
+func getSmartctlHealth(ctx context.Context, device string, health *DiskHealth) error {
+	output, err := exec.CommandContext(ctx, SmartctlPath, "--json", "-n", "standby", "-a", device).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("smartctl %s: %v", device, ctx.Err())
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("smartctl %s failed: %v", device, err)
+		}
+		if smartctlStandby(exitErr.ExitCode(), output) {
+			return nil
+		}
+		if exitErr.ExitCode()&smartctlFatalExitMask != 0 || len(output) == 0 {
+			return fmt.Errorf("smartctl %s failed: %v", device, err)
+		}
+	}
+
+	return parseSmartctlJSON(output, health)
+}
+
+// smartctlStandby 判断smartctl是否因-n standby而未读取SMART
+// 此时退出码位1置位，且没有JSON输出或消息为"Device is in STANDBY mode, exit(2)"（SLEEP等模式同理）
+func smartctlStandby(exitCode int, output []byte) bool {
+	if exitCode&smartctlOpenFailedBit == 0 {
+		return false
+	}
+
+	var result struct {
+		Smartctl struct {
+			Messages []struct {
+				String string `json:"string"`
+			} `json:"messages"`
+		} `json:"smartctl"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return true
+	}
+	for _, message := range result.Smartctl.Messages {
+		if strings.HasPrefix(message.String, "Device is in ") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSmartctlJSON 解析smartctl JSON输出，只覆盖smartctl提供了的字段
+func parseSmartctlJSON(data []byte, health *DiskHealth) error {
+	var smart smartctlOutput
+	if err := json.Unmarshal(data, &smart); err != nil {
+		return fmt.Errorf("failed to parse smartctl output: %v", err)
+	}
+
+	if smart.ModelName != "" {
+		health.Model = smart.ModelName
+	}
+	if smart.SerialNumber != "" {
+		health.Serial = smart.SerialNumber
+	}
+	if smart.FirmwareVersion != "" {
+		health.Firmware = smart.FirmwareVersion
+	}
+	if health.Interface == "" {
+		health.Interface = smart.Device.Protocol
+	}
+	if smart.UserCapacity.Bytes > 0 {
+		health.Capacity = smart.UserCapacity.Bytes
+	} else if smart.NVMeTotalCapacity > 0 {
+		health.Capacity = smart.NVMeTotalCapacity
+	}
+
+	health.Temperature = smart.Temperature.Current
+	health.PowerOnHours = smart.PowerOnTime.Hours
+	health.PowerCycles = smart.PowerCycleCount
+
+	var remainingLife float64
+	var lifeKnown bool
+	if nvme := smart.NVMeHealth; nvme != nil {
+		// percentage_used可超过100，表示已超出厂商标称寿命
+		remainingLife, lifeKnown = 100-float64(nvme.PercentageUsed), true
+		health.TotalBytesWritten = nvme.DataUnitsWritten * nvmeDataUnit
+		health.TotalBytesRead = nvme.DataUnitsRead * nvmeDataUnit
+		health.CriticalWarning = nvme.CriticalWarning != 0
+		if health.Temperature == 0 {
+			health.Temperature = float64(nvme.Temperature)
+		}
+		if health.PowerOnHours == 0 {
+			health.PowerOnHours = nvme.PowerOnHours
+		}
+		if health.PowerCycles == 0 {
+			health.PowerCycles = nvme.PowerCycles
+		}
+	} else {
+		remainingLife, lifeKnown = parseATAHealth(&smart, health)
+	}
+
+	if smart.SmartStatus != nil && !smart.SmartStatus.Passed {
+		health.CriticalWarning = true
+	}
+
+	if lifeKnown {
+		if remainingLife < 0 {
+			remainingLife = 0
+		}
+		health.RemainingLife = remainingLife
+		health.HealthPercentage = remainingLife
+	}
+	if health.CriticalWarning {
+		health.HealthPercentage = 0
+	}
+
+	return nil
+}
+
+// parseATAHealth 从ATA属性与设备统计中解析写入量、磨损等信息，返回剩余寿命百分比及其是否已知
+// 机械硬盘没有磨损指标
+func parseATAHealth(smart *smartctlOutput, health *DiskHealth) (float64, bool) {
+	blockSize := smart.LogicalBlockSize
+	if blockSize == 0 {
+		blockSize = 512
+	}
+
+	var remainingLife float64
+	var lifeKnown bool
+	attributes := make(map[int]smartctlATAAttribute)
+	for _, attribute := range smart.ATAAttributes.Table {
+		attributes[attribute.ID] = attribute
+		if attribute.WhenFailed == "now" {
+			health.CriticalWarning = true
+		}
+
+		switch {
+		case attribute.ID == 173 || attribute.ID == 177:
+			health.WearLevelingCount = attribute.Raw.Value
+		case attribute.ID == 171 || attribute.ID == 181:
+			health.ProgramFailCount = attribute.Raw.Value
+		case attribute.ID == 172 || attribute.ID == 182:
+			health.EraseFailCount = attribute.Raw.Value
+		case attribute.ID == 241 || attribute.Name == "Total_LBAs_Written": // Crucial/Micron使用246
+			health.TotalBytesWritten = ataTotalBytes(attribute, blockSize)
+		case attribute.ID == 242 || attribute.Name == "Total_LBAs_Read":
+			health.TotalBytesRead = ataTotalBytes(attribute, blockSize)
+		}
+	}
+
+	for _, id := range ataRemainingLifeAttributes {
+		if attribute, ok := attributes[id]; ok && attribute.Value <= 100 {
+			remainingLife, lifeKnown = float64(attribute.Value), true
+			break
+		}
+	}
+
+	// ACS-3设备统计日志是标准化的，优先于厂商自定义属性
+	for _, page := range smart.ATAStatistics.Pages {
+		for _, entry := range page.Table {
+			if !entry.Flags.Valid {
+				continue
+			}
+			switch {
+			case page.Number == 1 && entry.Name == "Logical Sectors Written":
+				health.TotalBytesWritten = entry.Value * blockSize
+			case page.Number == 1 && entry.Name == "Logical Sectors Read":
+				health.TotalBytesRead = entry.Value * blockSize
+			case page.Number == 7 && entry.Name == "Percentage Used Endurance Indicator":
+				remainingLife, lifeKnown = 100-float64(entry.Value), true
+			}
+		}
+	}
+
+	return remainingLife, lifeKnown
+}
+
+// ataTotalBytes 根据属性名判断写入/读取量的单位，各厂商对241/242属性的单位不同
+func ataTotalBytes(attribute smartctlATAAttribute, blockSize uint64) uint64 {
+	name := attribute.Name
+	switch {
+	case strings.Contains(name, "32MiB"):
+		return attribute.Raw.Value * 32 << 20
+	case strings.Contains(name, "GiB"):
+		return attribute.Raw.Value << 30
+	case strings.Contains(name, "MiB"):
+		return attribute.Raw.Value << 20
+	default:
+		return attribute.Raw.Value * blockSize
+	}
+}
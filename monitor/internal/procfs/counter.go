@@ -0,0 +1,9 @@
+package procfs
+
+// CounterDelta 计算累计计数器的增量，计数器减小（回绕、重置或设备重新挂载）时返回0
+func CounterDelta(last, current uint64) uint64 {
+	if current < last {
+		return 0
+	}
+	return current - last
+}
@@ -0,0 +1,171 @@
+// Package procfs 提供各监控包共用的procfs/sysfs解析工具
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// procSelfMountinfo 当前进程的挂载信息
+const procSelfMountinfo = "/proc/self/mountinfo"
+
+// MountInfo /proc/self/mountinfo中的一条挂载记录
+type MountInfo struct {
+	Major        uint32 // 设备主设备号
+	Minor        uint32 // 设备次设备号
+	Root         string // 挂载的文件系统内路径，bind mount或容器中的cgroup挂载时不为"/"
+	Mountpoint   string // 挂载点
+	Options      string // 单个挂载点的选项 (如rw,relatime)
+	FSType       string // 文件系统类型
+	Source       string // 挂载源，如/dev/vda1
+	SuperOptions string // 超级块选项，cgroup v1中包含挂载的控制器
+}
+
+// ReadMountInfo 解析/proc/self/mountinfo
+// 格式: "36 32 0:32 / /sys/fs/cgroup/memory rw,relatime shared:1 - cgroup cgroup rw,memory"
+// 可选字段数量不定，以"-"分隔
+func ReadMountInfo() ([]MountInfo, error) {
+	file, err := os.Open(procSelfMountinfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", procSelfMountinfo, err)
+	}
+	defer file.Close()
+
+	var mounts []MountInfo
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if mount, ok := parseMountInfoLine(scanner.Text()); ok {
+			mounts = append(mounts, mount)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return mounts, nil
+}
+
+// parseMountInfoLine 解析mountinfo中的一行
+func parseMountInfoLine(line string) (MountInfo, bool) {
+	fields := strings.Fields(line)
+
+	separator := -1
+	for i := 6; i < len(fields); i++ {
+		if fields[i] == "-" {
+			separator = i
+			break
+		}
+	}
+	if separator < 0 || separator+2 >= len(fields) {
+		return MountInfo{}, false
+	}
+
+	major, minor, ok := strings.Cut(fields[2], ":")
+	if !ok {
+		return MountInfo{}, false
+	}
+	majorNum, err := strconv.ParseUint(major, 10, 32)
+	if err != nil {
+		return MountInfo{}, false
+	}
+	minorNum, err := strconv.ParseUint(minor, 10, 32)
+	if err != nil {
+		return MountInfo{}, false
+	}
+
+	mount := MountInfo{
+		Major:      uint32(majorNum),
+		Minor:      uint32(minorNum),
+		Root:       UnescapePath(fields[3]),
+		Mountpoint: UnescapePath(fields[4]),
+		Options:    fields[5],
+		FSType:     fields[separator+1],
+		Source:     UnescapePath(fields[separator+2]),
+	}
+	if separator+3 < len(fields) {
+		mount.SuperOptions = fields[separator+3]
+	}
+	return mount, true
+}
+
+// UnescapePath 还原mountinfo、/proc/swaps等procfs文件中八进制转义的路径字符（如 \040 表示空格）
+func UnescapePath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if value, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		builder.WriteByte(path[i])
+	}
+	return builder.String()
+}
+
+// ParseCPUList 解析"0-3,8,10-11"格式的CPU或NUMA节点列表，返回升序编号
+func ParseCPUList(list string) ([]int, error) {
+	var result []int
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return result, nil
+	}
+
+	for _, part := range strings.Split(list, ",") {
+		start, end, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu list %q: %v", list, err)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(end); err != nil {
+				return nil, fmt.Errorf("invalid cpu list %q: %v", list, err)
+			}
+		}
+		for i := first; i <= last; i++ {
+			result = append(result, i)
+		}
+	}
+
+	sort.Ints(result)
+	return result, nil
+}
+
+// ReadKeyValueFile 读取"key value"格式的文件（如memory.stat、cpu.stat、/proc/vmstat）
+// 非数值的行被忽略
+func ReadKeyValueFile(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,38 @@
+package procfs
+
+import "testing"
+
+func TestParseMountInfoLine(t *testing.T) {
+	mount, ok := parseMountInfoLine(`36 32 0:32 / /sys/fs/cgroup/memory rw,nosuid shared:15 - cgroup cgroup rw,memory`)
+	if !ok || mount.FSType != "cgroup" || mount.Mountpoint != "/sys/fs/cgroup/memory" || mount.SuperOptions != "rw,memory" {
+		t.Errorf("unexpected mount: %+v", mount)
+	}
+
+	mount, ok = parseMountInfoLine(`98 25 8:1 /data /mnt/my\040disk rw,relatime - ext4 /dev/sda1 rw`)
+	if !ok || mount.Major != 8 || mount.Minor != 1 || mount.Root != "/data" || mount.Mountpoint != "/mnt/my disk" || mount.Source != "/dev/sda1" {
+		t.Errorf("unexpected mount: %+v", mount)
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	got, err := ParseCPUList("8,0-3,10-11\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{0, 1, 2, 3, 8, 10, 11}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	if _, err := ParseCPUList("0-x"); err == nil {
+		t.Error("expected error for invalid cpu list")
+	}
+	if cpus, err := ParseCPUList(""); err != nil || len(cpus) != 0 {
+		t.Errorf("empty list: got %v, %v", cpus, err)
+	}
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"time"
+)
+
+// KernelMemoryInfo 内核内存特性统计: 大页、透明大页、KSM、zswap与zram (仅Linux)
+type KernelMemoryInfo struct {
+	HugePages   *HugePagesInfo `json:"hugepages"`    // 静态大页 (hugetlbfs)，不可用时为nil
+	THP         *THPInfo       `json:"thp"`          // 透明大页，不可用时为nil
+	KSM         *KSMInfo       `json:"ksm"`          // 内核同页合并，不可用时为nil
+	Zswap       *ZswapInfo     `json:"zswap"`        // zswap压缩交换缓存，不可用时为nil
+	Zram        []ZramDevice   `json:"zram"`         // zram压缩内存块设备
+	LastUpdated time.Time      `json:"last_updated"` // 最后更新时间
+}
+
+// HugePagesInfo 静态大页信息
+type HugePagesInfo struct {
+	PageSize uint64         `json:"page_size"` // 默认大页大小 (bytes)
+	Total    uint64         `json:"total"`     // 默认大小的大页总数 (HugePages_Total)
+	Free     uint64         `json:"free"`      // 空闲大页数 (HugePages_Free)
+	Reserved uint64         `json:"reserved"`  // 已预留未分配的大页数 (HugePages_Rsvd)
+	Surplus  uint64         `json:"surplus"`   // 超出nr_hugepages的临时大页数 (HugePages_Surp)
+	Hugetlb  uint64         `json:"hugetlb"`   // 所有大小的大页占用的内存 (bytes)
+	Sizes    []HugePageSize `json:"sizes"`     // 按大小统计
+}
+
+// HugePageSize 某一大小的大页池
+type HugePageSize struct {
+	PageSize   uint64 `json:"page_size"`  // 大页大小 (bytes)
+	Total      uint64 `json:"total"`      // 大页总数
+	Free       uint64 `json:"free"`       // 空闲大页数
+	Reserved   uint64 `json:"reserved"`   // 预留大页数
+	Surplus    uint64 `json:"surplus"`    // 临时大页数
+	Overcommit uint64 `json:"overcommit"` // 允许超额分配的最大临时大页数
+}
+
+// THPInfo 透明大页信息
+type THPInfo struct {
+	Enabled        string `json:"enabled"`          // 模式: always, madvise, never
+	Defrag         string `json:"defrag"`           // 碎片整理策略: always, defer, defer+madvise, madvise, never
+	ShmemEnabled   string `json:"shmem_enabled"`    // 共享内存/tmpfs策略
+	AnonHugePages  uint64 `json:"anon_huge_pages"`  // 匿名透明大页占用 (bytes)
+	ShmemHugePages uint64 `json:"shmem_huge_pages"` // 共享内存透明大页占用 (bytes)
+	FileHugePages  uint64 `json:"file_huge_pages"`  // 文件页透明大页占用 (bytes)
+
+	// khugepaged后台合并线程
+	PagesToScan        uint64 `json:"pages_to_scan"`        // 每轮扫描页数
+	ScanSleepMillisecs uint64 `json:"scan_sleep_millisecs"` // 扫描间隔 (毫秒)
+	PagesCollapsed     uint64 `json:"pages_collapsed"`      // 累计合并成的大页数
+	FullScans          uint64 `json:"full_scans"`           // 累计完整扫描次数
+
+	// /proc/vmstat累计计数器
+	FaultAlloc          uint64 `json:"fault_alloc"`           // 缺页时成功分配大页次数
+	FaultFallback       uint64 `json:"fault_fallback"`        // 缺页时回退到小页次数
+	CollapseAlloc       uint64 `json:"collapse_alloc"`        // khugepaged成功分配大页次数
+	CollapseAllocFailed uint64 `json:"collapse_alloc_failed"` // khugepaged分配大页失败次数
+	SplitPage           uint64 `json:"split_page"`            // 大页被拆分次数
+}
+
+// KSMInfo 内核同页合并 (Kernel Samepage Merging) 信息
+type KSMInfo struct {
+	Running       bool    `json:"running"`        // 是否正在合并 (run=1)
+	PagesShared   uint64  `json:"pages_shared"`   // 被共享的物理页数
+	PagesSharing  uint64  `json:"pages_sharing"`  // 共享这些物理页的映射数，即节省的页数
+	PagesUnshared uint64  `json:"pages_unshared"` // 重复检查但内容唯一的页数
+	PagesVolatile uint64  `json:"pages_volatile"` // 变化太快无法合并的页数
+	FullScans     uint64  `json:"full_scans"`     // 完整扫描次数
+	Saved         uint64  `json:"saved"`          // 节省的内存 (bytes) = PagesSharing * 页大小
+	SharingRatio  float64 `json:"sharing_ratio"`  // 平均每个共享页被映射的次数 (PagesSharing / PagesShared)
+}
+
+// ZswapInfo zswap压缩交换缓存信息
+type ZswapInfo struct {
+	Enabled          bool    `json:"enabled"`           // 是否启用
+	Compressor       string  `json:"compressor"`        // 压缩算法
+	MaxPoolPercent   int     `json:"max_pool_percent"`  // 压缩池最多占用内存的百分比
+	PoolSize         uint64  `json:"pool_size"`         // 压缩池实际占用内存 (bytes)
+	StoredSize       uint64  `json:"stored_size"`       // 压缩前的数据量 (bytes)
+	CompressionRatio float64 `json:"compression_ratio"` // 压缩比 = StoredSize / PoolSize
+	StoreCount       uint64  `json:"store_count"`       // 累计存入页数 (zswpout)
+	LoadCount        uint64  `json:"load_count"`        // 累计取回页数 (zswpin)
+	WritebackCount   uint64  `json:"writeback_count"`   // 累计写回到交换设备的页数 (zswpwb)
+}
+
+// ZramDevice zram压缩内存块设备
+type ZramDevice struct {
+	Name             string  `json:"name"`              // 设备名，如zram0
+	DiskSize         uint64  `json:"disk_size"`         // 设备容量 (bytes)
+	Algorithm        string  `json:"algorithm"`         // 压缩算法
+	OrigDataSize     uint64  `json:"orig_data_size"`    // 存入的未压缩数据量 (bytes)
+	ComprDataSize    uint64  `json:"compr_data_size"`   // 压缩后的数据量 (bytes)
+	MemUsedTotal     uint64  `json:"mem_used_total"`    // 实际占用内存，含分配器开销与碎片 (bytes)
+	MemLimit         uint64  `json:"mem_limit"`         // 内存占用上限 (bytes)，0表示不限制
+	MemUsedMax       uint64  `json:"mem_used_max"`      // 内存占用峰值 (bytes)
+	SamePages        uint64  `json:"same_pages"`        // 内容相同（如全零）而无需存储的页数
+	HugePages        uint64  `json:"huge_pages"`        // 无法压缩而原样存储的页数
+	CompressionRatio float64 `json:"compression_ratio"` // 压缩比 = OrigDataSize / ComprDataSize
+	EffectiveRatio   float64 `json:"effective_ratio"`   // 实际节省比 = OrigDataSize / MemUsedTotal，反映真实内存成本
+	IsSwap           bool    `json:"is_swap"`           // 是否作为交换设备使用
+}
+
+// GetKernelMemoryInfo 获取大页、透明大页、KSM、zswap与zram统计
+func GetKernelMemoryInfo() (*KernelMemoryInfo, error) {
+	info := &KernelMemoryInfo{
+		LastUpdated: time.Now(),
+	}
+
+	err := getPlatformKernelMemoryInfo(info)
+	if err != nil {
+		return nil, err
+	}
+
+	if ksm := info.KSM; ksm != nil && ksm.PagesShared > 0 {
+		ksm.SharingRatio = float64(ksm.PagesSharing) / float64(ksm.PagesShared)
+	}
+	if zswap := info.Zswap; zswap != nil && zswap.PoolSize > 0 {
+		zswap.CompressionRatio = float64(zswap.StoredSize) / float64(zswap.PoolSize)
+	}
+
+	return info, nil
+}
+
+// GetZramDevices 获取zram设备统计
+func GetZramDevices() ([]ZramDevice, error) {
+	return getPlatformZramDevices()
+}
+
+// calculateZramRatios 计算zram压缩比与实际节省比
+func calculateZramRatios(device *ZramDevice) {
+	if device.ComprDataSize > 0 {
+		device.CompressionRatio = float64(device.OrigDataSize) / float64(device.ComprDataSize)
+	}
+	if device.MemUsedTotal > 0 {
+		device.EffectiveRatio = float64(device.OrigDataSize) / float64(device.MemUsedTotal)
+	}
+}
@@ -0,0 +1,262 @@
+//go:build linux
+
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 内核内存特性sysfs路径
+const (
+	sysHugePagesPath = "/sys/kernel/mm/hugepages"
+	sysTHPPath       = "/sys/kernel/mm/transparent_hugepage"
+	sysKSMPath       = "/sys/kernel/mm/ksm"
+	sysZswapPath     = "/sys/module/zswap/parameters"
+	debugZswapPath   = "/sys/kernel/debug/zswap"
+	sysBlockPath     = "/sys/block"
+)
+
+// getPlatformKernelMemoryInfo 获取平台内核内存特性统计
+func getPlatformKernelMemoryInfo(info *KernelMemoryInfo) error {
+	meminfo, err := readMeminfo()
+	if err != nil {
+		return err
+	}
+	vmstat, err := readVmstat()
+	if err != nil {
+		vmstat = make(map[string]uint64)
+	}
+
+	info.HugePages = readHugePages(meminfo)
+	info.THP = readTHP(meminfo, vmstat)
+	info.KSM = readKSM()
+	info.Zswap = readZswap(meminfo, vmstat)
+	info.Zram, _ = getPlatformZramDevices()
+
+	return nil
+}
+
+// readHugePages 从/proc/meminfo与/sys/kernel/mm/hugepages读取静态大页信息
+func readHugePages(meminfo map[string]uint64) *HugePagesInfo {
+	pageSize, ok := meminfo["Hugepagesize"]
+	if !ok {
+		return nil
+	}
+
+	hugePages := &HugePagesInfo{
+		PageSize: pageSize,
+		Total:    meminfo["HugePages_Total"],
+		Free:     meminfo["HugePages_Free"],
+		Reserved: meminfo["HugePages_Rsvd"],
+		Surplus:  meminfo["HugePages_Surp"],
+		Hugetlb:  meminfo["Hugetlb"],
+	}
+
+	// 目录名格式: hugepages-2048kB
+	dirs, _ := filepath.Glob(filepath.Join(sysHugePagesPath, "hugepages-*kB"))
+	for _, dir := range dirs {
+		sizeKB, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(dir), "hugepages-"), "kB"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		size := HugePageSize{
+			PageSize:   sizeKB * 1024,
+			Total:      readSysfsUint(filepath.Join(dir, "nr_hugepages")),
+			Free:       readSysfsUint(filepath.Join(dir, "free_hugepages")),
+			Reserved:   readSysfsUint(filepath.Join(dir, "resv_hugepages")),
+			Surplus:    readSysfsUint(filepath.Join(dir, "surplus_hugepages")),
+			Overcommit: readSysfsUint(filepath.Join(dir, "nr_overcommit_hugepages")),
+		}
+		hugePages.Sizes = append(hugePages.Sizes, size)
+	}
+
+	// 4.16之前的内核没有Hugetlb行
+	if _, ok := meminfo["Hugetlb"]; !ok {
+		for _, size := range hugePages.Sizes {
+			hugePages.Hugetlb += size.Total * size.PageSize
+		}
+	}
+
+	return hugePages
+}
+
+// readTHP 读取透明大页模式、khugepaged与vmstat统计
+func readTHP(meminfo, vmstat map[string]uint64) *THPInfo {
+	enabled, err := os.ReadFile(filepath.Join(sysTHPPath, "enabled"))
+	if err != nil {
+		return nil
+	}
+
+	thp := &THPInfo{
+		Enabled:        selectedOption(string(enabled)),
+		AnonHugePages:  meminfo["AnonHugePages"],
+		ShmemHugePages: meminfo["ShmemHugePages"],
+		FileHugePages:  meminfo["FileHugePages"],
+
+		PagesToScan:        readSysfsUint(filepath.Join(sysTHPPath, "khugepaged", "pages_to_scan")),
+		ScanSleepMillisecs: readSysfsUint(filepath.Join(sysTHPPath, "khugepaged", "scan_sleep_millisecs")),
+		PagesCollapsed:     readSysfsUint(filepath.Join(sysTHPPath, "khugepaged", "pages_collapsed")),
+		FullScans:          readSysfsUint(filepath.Join(sysTHPPath, "khugepaged", "full_scans")),
+
+		FaultAlloc:          vmstat["thp_fault_alloc"],
+		FaultFallback:       vmstat["thp_fault_fallback"],
+		CollapseAlloc:       vmstat["thp_collapse_alloc"],
+		CollapseAllocFailed: vmstat["thp_collapse_alloc_failed"],
+		SplitPage:           vmstat["thp_split_page"],
+	}
+
+	if defrag, err := os.ReadFile(filepath.Join(sysTHPPath, "defrag")); err == nil {
+		thp.Defrag = selectedOption(string(defrag))
+	}
+	if shmem, err := os.ReadFile(filepath.Join(sysTHPPath, "shmem_enabled")); err == nil {
+		thp.ShmemEnabled = selectedOption(string(shmem))
+	}
+
+	return thp
+}
+
+// readKSM 读取/sys/kernel/mm/ksm
+func readKSM() *KSMInfo {
+	run, err := os.ReadFile(filepath.Join(sysKSMPath, "run"))
+	if err != nil {
+		return nil
+	}
+
+	ksm := &KSMInfo{
+		Running:       strings.TrimSpace(string(run)) == "1",
+		PagesShared:   readSysfsUint(filepath.Join(sysKSMPath, "pages_shared")),
+		PagesSharing:  readSysfsUint(filepath.Join(sysKSMPath, "pages_sharing")),
+		PagesUnshared: readSysfsUint(filepath.Join(sysKSMPath, "pages_unshared")),
+		PagesVolatile: readSysfsUint(filepath.Join(sysKSMPath, "pages_volatile")),
+		FullScans:     readSysfsUint(filepath.Join(sysKSMPath, "full_scans")),
+	}
+	ksm.Saved = ksm.PagesSharing * uint64(os.Getpagesize())
+
+	return ksm
+}
+
+// readZswap 读取zswap参数与压缩池大小
+// 5.19+内核在/proc/meminfo提供Zswap/Zswapped，旧内核只能从debugfs读取（需要root并挂载debugfs）
+func readZswap(meminfo, vmstat map[string]uint64) *ZswapInfo {
+	enabled, err := os.ReadFile(filepath.Join(sysZswapPath, "enabled"))
+	if err != nil {
+		return nil
+	}
+
+	zswap := &ZswapInfo{
+		Enabled:        strings.TrimSpace(string(enabled)) == "Y",
+		StoreCount:     vmstat["zswpout"],
+		LoadCount:      vmstat["zswpin"],
+		WritebackCount: vmstat["zswpwb"],
+	}
+	if compressor, err := os.ReadFile(filepath.Join(sysZswapPath, "compressor")); err == nil {
+		zswap.Compressor = strings.TrimSpace(string(compressor))
+	}
+	zswap.MaxPoolPercent = int(readSysfsUint(filepath.Join(sysZswapPath, "max_pool_percent")))
+
+	if poolSize, ok := meminfo["Zswap"]; ok {
+		zswap.PoolSize = poolSize
+		zswap.StoredSize = meminfo["Zswapped"]
+	} else {
+		zswap.PoolSize = readSysfsUint(filepath.Join(debugZswapPath, "pool_total_size"))
+		zswap.StoredSize = readSysfsUint(filepath.Join(debugZswapPath, "stored_pages")) * uint64(os.Getpagesize())
+	}
+
+	return zswap
+}
+
+// getPlatformZramDevices 读取/sys/block/zram*设备统计
+func getPlatformZramDevices() ([]ZramDevice, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysBlockPath, "zram*"))
+	if err != nil {
+		return nil, err
+	}
+
+	swapDevices := make(map[string]bool)
+	if devices, err := readSwapDevices(); err == nil {
+		for _, device := range devices {
+			swapDevices[filepath.Base(device.Name)] = true
+		}
+	}
+
+	var devices []ZramDevice
+	for _, dir := range dirs {
+		device, ok := readZramDevice(dir)
+		if !ok {
+			continue
+		}
+		device.IsSwap = swapDevices[device.Name]
+		devices = append(devices, *device)
+	}
+
+	return devices, nil
+}
+
+// readZramDevice 读取单个zram设备，未初始化（disksize为0）的设备返回false
+// mm_stat格式 (4.1+): orig_data_size compr_data_size mem_used_total mem_limit mem_used_max same_pages pages_compacted huge_pages
+func readZramDevice(dir string) (*ZramDevice, bool) {
+	device := &ZramDevice{
+		Name:     filepath.Base(dir),
+		DiskSize: readSysfsUint(filepath.Join(dir, "disksize")),
+	}
+	if device.DiskSize == 0 {
+		return nil, false
+	}
+
+	if algorithm, err := os.ReadFile(filepath.Join(dir, "comp_algorithm")); err == nil {
+		device.Algorithm = selectedOption(string(algorithm))
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "mm_stat")); err == nil {
+		var values [8]uint64
+		for i, field := range strings.Fields(string(data)) {
+			if i >= len(values) {
+				break
+			}
+			values[i], _ = strconv.ParseUint(field, 10, 64)
+		}
+		device.OrigDataSize = values[0]
+		device.ComprDataSize = values[1]
+		device.MemUsedTotal = values[2]
+		device.MemLimit = values[3]
+		device.MemUsedMax = values[4]
+		device.SamePages = values[5]
+		device.HugePages = values[7]
+	} else {
+		// 4.1之前每项统计是单独的文件
+		device.OrigDataSize = readSysfsUint(filepath.Join(dir, "orig_data_size"))
+		device.ComprDataSize = readSysfsUint(filepath.Join(dir, "compr_data_size"))
+		device.MemUsedTotal = readSysfsUint(filepath.Join(dir, "mem_used_total"))
+		device.MemLimit = readSysfsUint(filepath.Join(dir, "mem_limit"))
+		device.MemUsedMax = readSysfsUint(filepath.Join(dir, "mem_used_max"))
+		device.SamePages = readSysfsUint(filepath.Join(dir, "zero_pages"))
+	}
+
+	calculateZramRatios(device)
+	return device, true
+}
+
+// selectedOption 返回sysfs选项列表中方括号标记的当前值，如"always [madvise] never"返回madvise
+func selectedOption(value string) string {
+	value = strings.TrimSpace(value)
+	start := strings.Index(value, "[")
+	end := strings.Index(value, "]")
+	if start >= 0 && end > start {
+		return value[start+1 : end]
+	}
+	return value
+}
+
+// readSysfsUint 读取单个整数值的sysfs文件，失败时返回0
+func readSysfsUint(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return value
+}
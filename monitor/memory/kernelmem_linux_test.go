@@ -0,0 +1,71 @@
+//go:build linux
+
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadZramDevice(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "zram0")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFixture(t, dir, "disksize", "4294967296\n")
+	writeFixture(t, dir, "comp_algorithm", "lzo lzo-rle lz4 [zstd]\n")
+	writeFixture(t, dir, "mm_stat", "  409600000   102400000   110000000        0   120000000     2048      12     37\n")
+
+	device, ok := readZramDevice(dir)
+	if !ok {
+		t.Fatal("expected initialized device")
+	}
+
+	want := ZramDevice{
+		Name:             "zram0",
+		DiskSize:         4294967296,
+		Algorithm:        "zstd",
+		OrigDataSize:     409600000,
+		ComprDataSize:    102400000,
+		MemUsedTotal:     110000000,
+		MemUsedMax:       120000000,
+		SamePages:        2048,
+		HugePages:        37,
+		CompressionRatio: 4,
+		EffectiveRatio:   409600000.0 / 110000000.0,
+	}
+	if *device != want {
+		t.Errorf("got  %+v\nwant %+v", *device, want)
+	}
+
+	// 未初始化的设备
+	empty := filepath.Join(t.TempDir(), "zram1")
+	if err := os.Mkdir(empty, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFixture(t, empty, "disksize", "0\n")
+	if _, ok := readZramDevice(empty); ok {
+		t.Error("expected uninitialized device to be skipped")
+	}
+}
+
+func TestSelectedOption(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"always [madvise] never\n", "madvise"},
+		{"[always] defer defer+madvise madvise never\n", "always"},
+		{"always within_size advise [never] deny force\n", "never"},
+		{"lzo lzo-rle lz4 lz4hc 842 [zstd]\n", "zstd"},
+		// 旧内核的comp_algorithm只有一个值且没有方括号
+		{"lzo\n", "lzo"},
+	}
+
+	for _, test := range tests {
+		if got := selectedOption(test.value); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.value, got, test.want)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package memory
 
 import (
 	"fmt"
-	"runtime"
 	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+	"github.com/singbox/manager/monitor/platform"
 )
 
 // MemoryInfo 内存基本信息
@@ -26,25 +28,56 @@ type MemoryInfo struct {
 
 // SwapInfo 交换空间信息
 type SwapInfo struct {
-	Total       uint64    `json:"total"`        // 总交换空间 (bytes)
-	Used        uint64    `json:"used"`         // 已用交换空间 (bytes)
-	Free        uint64    `json:"free"`         // 空闲交换空间 (bytes)
-	UsedPercent float64   `json:"used_percent"` // 使用率百分比
-	SwapIn      uint64    `json:"swap_in"`      // 换入次数
-	SwapOut     uint64    `json:"swap_out"`     // 换出次数
-	LastUpdated time.Time `json:"last_updated"` // 最后更新时间
+	Total       uint64       `json:"total"`         // 总交换空间 (bytes)
+	Used        uint64       `json:"used"`          // 已用交换空间 (bytes)
+	Free        uint64       `json:"free"`          // 空闲交换空间 (bytes)
+	UsedPercent float64      `json:"used_percent"`  // 使用率百分比
+	SwapIn      uint64       `json:"swap_in"`       // 累计换入页数
+	SwapOut     uint64       `json:"swap_out"`      // 累计换出页数
+	SwapInRate  float64      `json:"swap_in_rate"`  // 换入速率 (页/秒)，需两次采样
+	SwapOutRate float64      `json:"swap_out_rate"` // 换出速率 (页/秒)，需两次采样
+	Devices     []SwapDevice `json:"devices"`       // 交换设备列表
+	LastUpdated time.Time    `json:"last_updated"`  // 最后更新时间
+}
+
+// SwapDevice 交换设备（分区或文件）
+type SwapDevice struct {
+	Name     string `json:"name"`     // 设备或文件路径
+	Type     string `json:"type"`     // 类型: partition, file
+	Size     uint64 `json:"size"`     // 大小 (bytes)
+	Used     uint64 `json:"used"`     // 已用 (bytes)
+	Priority int    `json:"priority"` // 优先级，数值越大越优先使用
+	IsZram   bool   `json:"is_zram"`  // 是否为zram压缩内存设备
+
+	Zram *ZramDevice `json:"zram,omitempty"` // zram设备的压缩比与实际内存占用 (仅zram设备)
 }
 
-// MemoryPressure 内存压力信息 (macOS特有)
+// 内存压力级别
+const (
+	PressureLevelNormal   = "normal"
+	PressureLevelWarn     = "warn"
+	PressureLevelUrgent   = "urgent"
+	PressureLevelCritical = "critical"
+)
+
+// MemoryPressure 内存压力信息 (macOS: memory_pressure, Linux: PSI)
 type MemoryPressure struct {
 	Level            string    `json:"level"`             // 压力级别: normal, warn, urgent, critical
-	Percentage       float64   `json:"percentage"`        // 压力百分比
+	Percentage       float64   `json:"percentage"`        // 压力百分比 (Linux为some avg10，即最近10秒内有任务因内存停顿的时间占比)
 	PagesFreed       uint64    `json:"pages_freed"`       // 释放的页面数
 	PagesPurged      uint64    `json:"pages_purged"`      // 清除的页面数
 	PagesSpeculative uint64    `json:"pages_speculative"` // 推测页面数
 	LastUpdated      time.Time `json:"last_updated"`      // 最后更新时间
+
+	PSI *PSIInfo `json:"psi,omitempty"` // 原始PSI数据 (仅Linux)
 }
 
+// PSIInfo Linux压力停顿信息 (Pressure Stall Information)，Source为/proc/pressure/memory或cgroup的memory.pressure
+type PSIInfo = platform.PressureInfo
+
+// PressureStall 一行PSI统计
+type PressureStall = platform.PressureStall
+
 // MemoryStats 内存详细统计
 type MemoryStats struct {
 	PageSize      uint64    `json:"page_size"`      // 页面大小 (bytes)
@@ -58,6 +91,19 @@ type MemoryStats struct {
 	Hits          uint64    `json:"hits"`           // 命中次数
 	Purges        uint64    `json:"purges"`         // 清除次数
 	LastUpdated   time.Time `json:"last_updated"`   // 最后更新时间
+
+	// 以下字段目前仅Linux提供 (/proc/vmstat)
+	MajorFaults       uint64  `json:"major_faults"`        // 需要磁盘I/O的主缺页次数
+	PagesScanned      uint64  `json:"pages_scanned"`       // 页面回收扫描页数 (pgscan)
+	PagesReclaimed    uint64  `json:"pages_reclaimed"`     // 页面回收成功页数 (pgsteal)
+	DirectReclaims    uint64  `json:"direct_reclaims"`     // 直接回收扫描页数（分配路径上同步回收，直接造成延迟）
+	CompactStalls     uint64  `json:"compact_stalls"`      // 内存规整导致的分配停顿次数
+	FaultRate         float64 `json:"fault_rate"`          // 缺页速率 (次/秒)
+	MajorFaultRate    float64 `json:"major_fault_rate"`    // 主缺页速率 (次/秒)
+	ScanRate          float64 `json:"scan_rate"`           // 回收扫描速率 (页/秒)
+	ReclaimRate       float64 `json:"reclaim_rate"`        // 回收速率 (页/秒)
+	DirectReclaimRate float64 `json:"direct_reclaim_rate"` // 直接回收扫描速率 (页/秒)
+	CompactStallRate  float64 `json:"compact_stall_rate"`  // 内存规整停顿速率 (次/秒)
 }
 
 // VirtualMemoryInfo 虚拟内存信息
@@ -67,11 +113,16 @@ type VirtualMemoryInfo struct {
 	Free        uint64    `json:"free"`         // 空闲虚拟内存 (bytes)
 	UsedPercent float64   `json:"used_percent"` // 使用率百分比
 	LastUpdated time.Time `json:"last_updated"` // 最后更新时间
+
+	// 以下字段目前仅Linux提供，Total/Used分别对应CommitLimit/Committed_AS
+	OvercommitMode int  `json:"overcommit_mode"` // vm.overcommit_memory: 0启发式, 1总是允许, 2严格限制
+	OverCommitted  bool `json:"over_committed"`  // 已承诺内存是否超过CommitLimit
 }
 
 var (
 	lastMemoryStats *MemoryStats
 	lastStatsTime   time.Time
+	lastSwapInfo    *SwapInfo
 )
 
 // GetInfo 获取内存基本信息
@@ -96,6 +147,7 @@ func GetInfo() (*MemoryInfo, error) {
 }
 
 // GetSwapInfo 获取交换空间信息
+// 换入/换出速率基于与上一次调用之间的计数器差值，首次调用时为0
 func GetSwapInfo() (*SwapInfo, error) {
 	info := &SwapInfo{
 		LastUpdated: time.Now(),
@@ -112,9 +164,42 @@ func GetSwapInfo() (*SwapInfo, error) {
 		info.UsedPercent = float64(info.Used) / float64(info.Total) * 100
 	}
 
+	// 计算换入换出速率
+	if lastSwapInfo != nil {
+		calculateSwapRates(lastSwapInfo, info)
+	}
+	lastSwapInfo = info
+
 	return info, nil
 }
 
+// GetSwapInfoWithDuration 获取交换空间信息，首次调用时等待duration采样以得到换入换出速率
+func GetSwapInfoWithDuration(duration time.Duration) (*SwapInfo, error) {
+	if lastSwapInfo == nil {
+		if _, err := GetSwapInfo(); err != nil {
+			return nil, err
+		}
+		time.Sleep(duration)
+	}
+
+	return GetSwapInfo()
+}
+
+// calculateSwapRates 根据两次采样计算换入换出速率 (页/秒)
+func calculateSwapRates(last, current *SwapInfo) {
+	elapsed := current.LastUpdated.Sub(last.LastUpdated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	if current.SwapIn >= last.SwapIn {
+		current.SwapInRate = float64(current.SwapIn-last.SwapIn) / elapsed
+	}
+	if current.SwapOut >= last.SwapOut {
+		current.SwapOutRate = float64(current.SwapOut-last.SwapOut) / elapsed
+	}
+}
+
 // GetStats 获取内存详细统计
 func GetStats() (*MemoryStats, error) {
 	stats := &MemoryStats{
@@ -122,8 +207,36 @@ func GetStats() (*MemoryStats, error) {
 	}
 
 	err := getPlatformMemoryStats(stats)
+	if err != nil {
+		return stats, err
+	}
 
-	return stats, err
+	// 与上一次调用比较计算速率
+	if lastMemoryStats != nil {
+		calculateMemoryStatsRates(lastMemoryStats, stats, stats.LastUpdated.Sub(lastStatsTime).Seconds())
+	}
+	lastMemoryStats = stats
+	lastStatsTime = stats.LastUpdated
+
+	return stats, nil
+}
+
+// calculateMemoryStatsRates 根据两次采样计算缺页与回收速率
+func calculateMemoryStatsRates(last, current *MemoryStats, elapsed float64) {
+	if elapsed <= 0 {
+		return
+	}
+
+	rate := func(last, current uint64) float64 {
+		return float64(procfs.CounterDelta(last, current)) / elapsed
+	}
+
+	current.FaultRate = rate(last.Faults, current.Faults)
+	current.MajorFaultRate = rate(last.MajorFaults, current.MajorFaults)
+	current.ScanRate = rate(last.PagesScanned, current.PagesScanned)
+	current.ReclaimRate = rate(last.PagesReclaimed, current.PagesReclaimed)
+	current.DirectReclaimRate = rate(last.DirectReclaims, current.DirectReclaims)
+	current.CompactStallRate = rate(last.CompactStalls, current.CompactStalls)
 }
 
 // GetVirtualMemoryInfo 获取虚拟内存信息
@@ -146,17 +259,13 @@ func GetVirtualMemoryInfo() (*VirtualMemoryInfo, error) {
 	return info, nil
 }
 
-// GetMemoryPressure 获取内存压力信息 (仅macOS)
+// GetMemoryPressure 获取内存压力信息 (macOS与Linux)
 func GetMemoryPressure() (*MemoryPressure, error) {
-	if runtime.GOOS != "darwin" {
-		return nil, fmt.Errorf("memory pressure monitoring only supported on macOS")
-	}
-
 	pressure := &MemoryPressure{
 		LastUpdated: time.Now(),
 	}
 
-	err := getDarwinMemoryPressure(pressure)
+	err := getPlatformMemoryPressure(pressure)
 	return pressure, err
 }
 
@@ -184,11 +293,19 @@ func GetDetailedInfo() (map[string]interface{}, error) {
 		result["stats"] = stats
 	}
 
-	// macOS特有的内存压力信息
-	if runtime.GOOS == "darwin" {
-		if pressure, err := GetMemoryPressure(); err == nil {
-			result["pressure"] = pressure
-		}
+	// 内存压力信息 (macOS与Linux)
+	if pressure, err := GetMemoryPressure(); err == nil {
+		result["pressure"] = pressure
+	}
+
+	// OOM计数器 (仅Linux)
+	if oom, err := GetOOMStats(); err == nil {
+		result["oom"] = oom
+	}
+
+	// 大页、KSM、zswap与zram (仅Linux)
+	if kernel, err := GetKernelMemoryInfo(); err == nil {
+		result["kernel"] = kernel
 	}
 
 	return result, nil
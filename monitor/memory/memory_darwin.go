@@ -6,6 +6,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -27,6 +28,11 @@ func getPlatformMemoryStats(stats *MemoryStats) error {
 	return getDarwinMemoryStats(stats)
 }
 
+// getPlatformMemoryPressure 获取平台内存压力信息
+func getPlatformMemoryPressure(pressure *MemoryPressure) error {
+	return getDarwinMemoryPressure(pressure)
+}
+
 // getPlatformVirtualMemoryInfo 获取平台虚拟内存信息
 func getPlatformVirtualMemoryInfo(info *VirtualMemoryInfo) error {
 	return getDarwinVirtualMemoryInfo(info)
@@ -460,3 +466,28 @@ func parseSize(sizeStr, unit string) (uint64, error) {
 
 	return uint64(size * float64(multiplier)), nil
 }
+
+// getPlatformOOMStats macOS不支持OOM计数器
+func getPlatformOOMStats(stats *OOMStats) error {
+	return fmt.Errorf("OOM kill detection not supported on macOS")
+}
+
+// openPlatformKmsg macOS没有/dev/kmsg
+func openPlatformKmsg(callback func(event OOMEvent)) (io.Closer, error) {
+	return nil, fmt.Errorf("kernel log not supported on macOS")
+}
+
+// getPlatformNUMANodes macOS不支持NUMA节点统计
+func getPlatformNUMANodes() ([]NUMANode, error) {
+	return nil, fmt.Errorf("NUMA node statistics not supported on macOS")
+}
+
+// getPlatformKernelMemoryInfo macOS不支持内核内存特性统计
+func getPlatformKernelMemoryInfo(info *KernelMemoryInfo) error {
+	return fmt.Errorf("kernel memory statistics not supported on macOS")
+}
+
+// getPlatformZramDevices macOS没有zram
+func getPlatformZramDevices() ([]ZramDevice, error) {
+	return nil, fmt.Errorf("zram not supported on macOS")
+}
@@ -3,50 +3,421 @@
 package memory
 
 import (
+	"bufio"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/singbox/manager/monitor/cgroup"
+	"github.com/singbox/manager/monitor/internal/procfs"
+	"github.com/singbox/manager/monitor/platform"
+)
+
+// Linux procfs路径
+const (
+	procMeminfoPath  = "/proc/meminfo"
+	procZoneinfoPath = "/proc/zoneinfo"
+	procSwapsPath    = "/proc/swaps"
+	procVmstatPath   = "/proc/vmstat"
+	procOvercommit   = "/proc/sys/vm/overcommit_memory"
 )
 
 // getPlatformMemoryInfo 获取平台内存信息
 func getPlatformMemoryInfo(info *MemoryInfo) error {
-	return fmt.Errorf("Linux memory info not implemented yet")
+	return getLinuxMemoryInfo(info)
 }
 
 // getPlatformSwapInfo 获取平台交换分区信息
 func getPlatformSwapInfo(info *SwapInfo) error {
-	return fmt.Errorf("Linux swap info not implemented yet")
+	return getLinuxSwapInfo(info)
 }
 
 // getPlatformMemoryStats 获取平台内存统计
 func getPlatformMemoryStats(stats *MemoryStats) error {
-	return fmt.Errorf("Linux memory stats not implemented yet")
+	return getLinuxMemoryStats(stats)
 }
 
 // getPlatformVirtualMemoryInfo 获取平台虚拟内存信息
 func getPlatformVirtualMemoryInfo(info *VirtualMemoryInfo) error {
-	return fmt.Errorf("Linux virtual memory info not implemented yet")
+	return getLinuxVirtualMemoryInfo(info)
 }
 
-// getLinuxMemoryInfo 获取Linux内存信息 (占位符实现)
+// getLinuxMemoryInfo 从/proc/meminfo获取内存信息
 func getLinuxMemoryInfo(info *MemoryInfo) error {
-	return fmt.Errorf("Linux memory info not implemented yet")
+	meminfo, err := readMeminfo()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procMeminfoPath, err)
+	}
+
+	return fillMemoryInfo(info, meminfo)
 }
 
-// getLinuxSwapInfo 获取Linux交换空间信息 (占位符实现)
+// fillMemoryInfo 按meminfo计算内存使用情况
+func fillMemoryInfo(info *MemoryInfo, meminfo map[string]uint64) error {
+	total, ok := meminfo["MemTotal"]
+	if !ok {
+		return fmt.Errorf("MemTotal not found in %s", procMeminfoPath)
+	}
+
+	info.Total = total
+	info.Free = meminfo["MemFree"]
+	info.Buffers = meminfo["Buffers"]
+	info.Shared = meminfo["Shmem"]
+	info.Active = meminfo["Active"]
+	info.Inactive = meminfo["Inactive"]
+
+	// 与free(1)一致: 缓存 = Cached + SReclaimable（可回收的slab）
+	info.Cached = meminfo["Cached"] + meminfo["SReclaimable"]
+
+	// MemAvailable自3.14内核提供，旧内核按内核算法估算
+	if available, ok := meminfo["MemAvailable"]; ok {
+		info.Available = available
+	} else {
+		info.Available = estimateAvailable(meminfo, readLowWatermark(procZoneinfoPath))
+	}
+	if info.Available > info.Total {
+		info.Available = info.Total
+	}
+
+	// 与procps-ng 4.x的free(1)一致: used = total - available
+	info.Used = info.Total - info.Available
+
+	return nil
+}
+
+// estimateAvailable 在没有MemAvailable的旧内核上估算可用内存
+// 算法同内核si_mem_available(): 空闲页 - 低水位 + 可回收的页缓存与slab
+func estimateAvailable(meminfo map[string]uint64, lowWatermark uint64) uint64 {
+	available := int64(meminfo["MemFree"]) - int64(lowWatermark)
+
+	// 页缓存中至少保留一半或低水位，其余视为可回收
+	pageCache := meminfo["Active(file)"] + meminfo["Inactive(file)"]
+	if pageCache == 0 {
+		// 2.6.28之前没有按文件/匿名拆分LRU
+		pageCache = meminfo["Cached"] + meminfo["Buffers"]
+	}
+	available += int64(pageCache) - int64(minUint64(pageCache/2, lowWatermark))
+
+	reclaimable := meminfo["SReclaimable"]
+	available += int64(reclaimable) - int64(minUint64(reclaimable/2, lowWatermark))
+
+	if available < 0 {
+		return 0
+	}
+	return uint64(available)
+}
+
+// readLowWatermark 从zoneinfo汇总各内存区域的低水位 (bytes)
+func readLowWatermark(path string) uint64 {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var pages uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "low" {
+			if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+				pages += value
+			}
+		}
+	}
+
+	return pages * uint64(os.Getpagesize())
+}
+
+// readMeminfo 读取/proc/meminfo
+func readMeminfo() (map[string]uint64, error) {
+	return readMeminfoFile(procMeminfoPath)
+}
+
+// readMeminfoFile 解析meminfo格式文件，带kB单位的值转换为字节，其余（如HugePages_Total）保持原值
+func readMeminfoFile(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	meminfo := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		fields := strings.Fields(parts[1])
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		meminfo[strings.TrimSpace(parts[0])] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return meminfo, nil
+}
+
+// minUint64 返回较小值
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// getLinuxSwapInfo 从/proc/meminfo、/proc/swaps与/proc/vmstat获取交换空间信息
 func getLinuxSwapInfo(info *SwapInfo) error {
-	return fmt.Errorf("Linux swap info not implemented yet")
+	meminfo, err := readMeminfo()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procMeminfoPath, err)
+	}
+
+	info.Total = meminfo["SwapTotal"]
+	info.Free = meminfo["SwapFree"]
+	if info.Total >= info.Free {
+		info.Used = info.Total - info.Free
+	}
+
+	// 每个交换设备的使用情况
+	devices, err := readSwapDevices()
+	if err == nil {
+		info.Devices = devices
+	}
+
+	// 累计换入换出页数
+	if vmstat, err := readVmstat(); err == nil {
+		info.SwapIn = vmstat["pswpin"]
+		info.SwapOut = vmstat["pswpout"]
+	}
+
+	return nil
+}
+
+// readSwapDevices 读取/proc/swaps
+func readSwapDevices() ([]SwapDevice, error) {
+	return readSwapsFile(procSwapsPath)
 }
 
-// getLinuxMemoryStats 获取Linux内存详细统计 (占位符实现)
+// readSwapsFile 解析swaps格式文件
+// 格式: "Filename Type Size Used Priority"，大小单位为kB，路径中的空格转义为\040
+func readSwapsFile(path string) ([]SwapDevice, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var devices []SwapDevice
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // 跳过标题行
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		device := SwapDevice{
+			Name: procfs.UnescapePath(fields[0]),
+			Type: fields[1],
+		}
+		if size, err := strconv.ParseUint(fields[2], 10, 64); err == nil {
+			device.Size = size * 1024
+		}
+		if used, err := strconv.ParseUint(fields[3], 10, 64); err == nil {
+			device.Used = used * 1024
+		}
+		device.Priority, _ = strconv.Atoi(fields[4])
+		device.IsZram = strings.HasPrefix(device.Name, "/dev/zram")
+		if device.IsZram {
+			if zram, ok := readZramDevice(filepath.Join(sysBlockPath, filepath.Base(device.Name))); ok {
+				zram.IsSwap = true
+				device.Zram = zram
+			}
+		}
+
+		devices = append(devices, device)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// readVmstat 读取/proc/vmstat计数器
+func readVmstat() (map[string]uint64, error) {
+	return procfs.ReadKeyValueFile(procVmstatPath)
+}
+
+// getLinuxMemoryStats 从/proc/meminfo与/proc/vmstat获取内存详细统计
 func getLinuxMemoryStats(stats *MemoryStats) error {
-	return fmt.Errorf("Linux memory stats not implemented yet")
+	meminfo, err := readMeminfo()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procMeminfoPath, err)
+	}
+	vmstat, err := readVmstat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procVmstatPath, err)
+	}
+
+	stats.PageSize = uint64(os.Getpagesize())
+	stats.TotalPages = meminfo["MemTotal"] / stats.PageSize
+	stats.FreePages = meminfo["MemFree"] / stats.PageSize
+	stats.ActivePages = meminfo["Active"] / stats.PageSize
+	stats.InactivePages = meminfo["Inactive"] / stats.PageSize
+	// Linux没有wired概念，使用不可回收（mlock等）页面近似
+	stats.WiredPages = meminfo["Unevictable"] / stats.PageSize
+
+	stats.Faults = vmstat["pgfault"]
+	stats.MajorFaults = vmstat["pgmajfault"]
+	stats.CompactStalls = vmstat["compact_stall"]
+	addReclaimCounters(stats, vmstat)
+
+	return nil
+}
+
+// addReclaimCounters 汇总vmstat中的页面扫描与回收计数
+func addReclaimCounters(stats *MemoryStats, vmstat map[string]uint64) {
+	// pgscan_*/pgsteal_* 按回收来源拆分（kswapd/direct/khugepaged，旧内核还按zone拆分），
+	// _anon/_file 是同一总数按页面类型的另一种拆分，不能重复累加
+	for key, value := range vmstat {
+		switch {
+		case strings.HasSuffix(key, "_anon"), strings.HasSuffix(key, "_file"), key == "pgscan_direct_throttle":
+			continue
+		case strings.HasPrefix(key, "pgscan_"):
+			stats.PagesScanned += value
+			if strings.HasPrefix(key, "pgscan_direct") {
+				stats.DirectReclaims += value
+			}
+		case strings.HasPrefix(key, "pgsteal_"):
+			stats.PagesReclaimed += value
+		}
+	}
 }
 
-// getLinuxVirtualMemoryInfo 获取Linux虚拟内存信息 (占位符实现)
+// getLinuxVirtualMemoryInfo 使用/proc/meminfo的提交内存记账获取虚拟内存信息
+// Total为CommitLimit，Used为Committed_AS（已承诺分配的虚拟内存）
 func getLinuxVirtualMemoryInfo(info *VirtualMemoryInfo) error {
-	return fmt.Errorf("Linux virtual memory info not implemented yet")
+	meminfo, err := readMeminfo()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procMeminfoPath, err)
+	}
+
+	info.Total = meminfo["CommitLimit"]
+	info.Used = meminfo["Committed_AS"]
+	if info.Total > info.Used {
+		info.Free = info.Total - info.Used
+	} else {
+		// 启发式超额提交模式下Committed_AS可以超过CommitLimit
+		info.OverCommitted = true
+	}
+
+	if mode, err := os.ReadFile(procOvercommit); err == nil {
+		info.OvercommitMode, _ = strconv.Atoi(strings.TrimSpace(string(mode)))
+	}
+
+	return nil
+}
+
+// PSI停顿百分比到压力级别的阈值，取avg10与avg60中的较大值比较，任一行达到即进入该级别:
+//
+//	级别      some    full
+//	warn      >= 5%   >= 1%
+//	urgent    >= 20%  >= 5%
+//	critical  >= 50%  >= 15%
+//
+// some表示有任务在等待内存（回收、换入、抖动），full表示所有非空闲任务同时停顿，
+// full意味着CPU时间被完全浪费，因此阈值更低
+var psiLevelThresholds = []struct {
+	level string
+	some  float64
+	full  float64
+}{
+	{PressureLevelCritical, 50, 15},
+	{PressureLevelUrgent, 20, 5},
+	{PressureLevelWarn, 5, 1},
+}
+
+// getPlatformMemoryPressure 获取平台内存压力信息
+func getPlatformMemoryPressure(pressure *MemoryPressure) error {
+	return getLinuxMemoryPressure(pressure)
+}
+
+// getLinuxMemoryPressure 通过PSI获取内存压力
+// 容器内使用容器cgroup的memory.pressure，否则使用系统级/proc/pressure/memory，PSI.Source记录实际来源
+func getLinuxMemoryPressure(pressure *MemoryPressure) error {
+	info, err := containerMemoryPressure()
+	if err != nil {
+		info, err = platform.GetPressure(platform.PressureResourceMemory)
+		if err != nil {
+			return err
+		}
+	}
+
+	pressure.PSI = info
+	pressure.Percentage = info.Some.Avg10
+	pressure.Level = psiLevel(info)
+	return nil
+}
+
+// containerMemoryPressure 读取容器所在cgroup v2的内存压力
+func containerMemoryPressure() (*platform.PressureInfo, error) {
+	path, err := platform.CurrentCgroup()
+	if err != nil {
+		return nil, err
+	}
+
+	var limits *cgroup.Info
+	if path != "/" {
+		limits, _ = cgroup.GetInfo()
+	}
+	if !useCgroupPressure(path, limits) {
+		return nil, fmt.Errorf("process is not in a container cgroup")
+	}
+	return platform.GetCgroupPressure(path, platform.PressureResourceMemory)
+}
+
+// useCgroupPressure 判断是否应使用进程所在cgroup的PSI代替系统级PSI
+// 启用了cgroup命名空间的容器中cgroup路径为"/"，memory.pressure位于挂载点下（宿主机根cgroup中与系统级一致）；
+// 其他路径仅在cgroup设置了内存上限时视为容器，宿主机上的systemd服务（如/system.slice/x.service）
+// 只反映该服务自身的停顿，会掩盖整机的内存压力
+func useCgroupPressure(path string, info *cgroup.Info) bool {
+	if path == "/" {
+		return true
+	}
+	return info != nil && info.Memory != nil && info.Memory.Limit > 0
 }
 
-// getDarwinMemoryPressure Linux平台不支持Darwin内存压力
-func getDarwinMemoryPressure(pressure *MemoryPressure) error {
-	return fmt.Errorf("Darwin memory pressure not available on Linux")
+// psiLevel 按psiLevelThresholds将PSI映射为压力级别
+func psiLevel(psi *PSIInfo) string {
+	some := math.Max(psi.Some.Avg10, psi.Some.Avg60)
+	var full float64
+	if psi.Full != nil {
+		full = math.Max(psi.Full.Avg10, psi.Full.Avg60)
+	}
+
+	for _, threshold := range psiLevelThresholds {
+		if some >= threshold.some || full >= threshold.full {
+			return threshold.level
+		}
+	}
+	return PressureLevelNormal
 }
@@ -0,0 +1,223 @@
+//go:build linux
+
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/singbox/manager/monitor/cgroup"
+)
+
+func TestUseCgroupPressure(t *testing.T) {
+	limited := &cgroup.Info{Memory: &cgroup.MemoryStats{Limit: 512 << 20}}
+	unlimited := &cgroup.Info{Memory: &cgroup.MemoryStats{}}
+
+	tests := []struct {
+		name string
+		path string
+		info *cgroup.Info
+		want bool
+	}{
+		{"namespaced container", "/", nil, true},
+		{"systemd service", "/system.slice/sing-box.service", unlimited, false},
+		{"user session", "/user.slice/user-1000.slice/session-2.scope", nil, false},
+		{"limited container", "/kubepods.slice/pod1234/cri-containerd-abcd.scope", limited, true},
+		{"no memory controller", "/docker/0123abcd", &cgroup.Info{}, false},
+	}
+
+	for _, test := range tests {
+		if got := useCgroupPressure(test.path, test.info); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// writeFixture 在临时目录写入测试用的procfs/sysfs文件
+func writeFixture(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFillMemoryInfo(t *testing.T) {
+	path := writeFixture(t, t.TempDir(), "meminfo", `MemTotal:        8000000 kB
+MemFree:         1000000 kB
+MemAvailable:    5000000 kB
+Buffers:          200000 kB
+Cached:          3000000 kB
+SwapCached:            0 kB
+Active:          2500000 kB
+Inactive:        2000000 kB
+Shmem:            100000 kB
+SReclaimable:     400000 kB
+HugePages_Total:       4
+Hugepagesize:       2048 kB
+`)
+
+	meminfo, err := readMeminfoFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meminfo["HugePages_Total"] != 4 {
+		t.Errorf("HugePages_Total = %d, want 4 (no unit)", meminfo["HugePages_Total"])
+	}
+
+	var info MemoryInfo
+	if err := fillMemoryInfo(&info, meminfo); err != nil {
+		t.Fatal(err)
+	}
+	if info.Total != 8000000*1024 || info.Available != 5000000*1024 {
+		t.Errorf("total=%d available=%d", info.Total, info.Available)
+	}
+	if want := uint64(3000000*1024 + 400000*1024); info.Cached != want {
+		t.Errorf("cached = %d, want %d", info.Cached, want)
+	}
+	if want := uint64(3000000 * 1024); info.Used != want {
+		t.Errorf("used = %d, want %d", info.Used, want)
+	}
+
+	if err := fillMemoryInfo(&info, map[string]uint64{"MemFree": 1}); err == nil {
+		t.Error("expected error without MemTotal")
+	}
+}
+
+func TestEstimateAvailable(t *testing.T) {
+	path := writeFixture(t, t.TempDir(), "zoneinfo", `Node 0, zone      DMA
+  pages free     3968
+        min      33
+        low      41
+        high     49
+Node 0, zone    DMA32
+  pages free     200000
+        min      5000
+        low      6250
+        high     7500
+  pagesets
+    cpu: 0
+              count: 10
+              high:  378
+`)
+	pageSize := uint64(os.Getpagesize())
+	lowWatermark := readLowWatermark(path)
+	if want := (41 + 6250) * pageSize; lowWatermark != want {
+		t.Fatalf("low watermark = %d, want %d", lowWatermark, want)
+	}
+
+	const mb = 1 << 20
+	meminfo := map[string]uint64{
+		"MemFree":        500 * mb,
+		"Active(file)":   300 * mb,
+		"Inactive(file)": 100 * mb,
+		"SReclaimable":   40 * mb,
+	}
+	low := uint64(50 * mb)
+	// 500-50 + 400-min(200,50) + 40-min(20,50)
+	if got, want := estimateAvailable(meminfo, low), uint64(820*mb); got != want {
+		t.Errorf("available = %d, want %d", got, want)
+	}
+
+	// 没有按文件/匿名拆分LRU的旧内核回退到Cached+Buffers
+	legacy := map[string]uint64{"MemFree": 100 * mb, "Cached": 60 * mb, "Buffers": 20 * mb}
+	if got, want := estimateAvailable(legacy, low), uint64(90*mb); got != want {
+		t.Errorf("legacy available = %d, want %d", got, want)
+	}
+
+	if got := estimateAvailable(map[string]uint64{"MemFree": mb}, low); got != 0 {
+		t.Errorf("available below watermark = %d, want 0", got)
+	}
+}
+
+func TestReadSwapsFile(t *testing.T) {
+	path := writeFixture(t, t.TempDir(), "swaps", `Filename				Type		Size		Used		Priority
+/swap\040file				file		2097148		1024		-2
+/dev/sda2                               partition	1048572		0		10
+/dev/zram0                              partition	4194300		2048		100
+`)
+
+	devices, err := readSwapsFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 3 {
+		t.Fatalf("expected 3 devices, got %d", len(devices))
+	}
+
+	want := []SwapDevice{
+		{Name: "/swap file", Type: "file", Size: 2097148 * 1024, Used: 1024 * 1024, Priority: -2},
+		{Name: "/dev/sda2", Type: "partition", Size: 1048572 * 1024, Priority: 10},
+		{Name: "/dev/zram0", Type: "partition", Size: 4194300 * 1024, Used: 2048 * 1024, Priority: 100, IsZram: true},
+	}
+	for i, device := range devices {
+		device.Zram = nil
+		if device != want[i] {
+			t.Errorf("device %d:\ngot  %+v\nwant %+v", i, device, want[i])
+		}
+	}
+}
+
+func TestAddReclaimCounters(t *testing.T) {
+	vmstat := map[string]uint64{
+		"pgscan_kswapd":          100,
+		"pgscan_direct":          40,
+		"pgscan_khugepaged":      5,
+		"pgscan_anon":            90,
+		"pgscan_file":            55,
+		"pgscan_direct_throttle": 7,
+		"pgsteal_kswapd":         80,
+		"pgsteal_direct":         30,
+		"pgsteal_anon":           60,
+		"pgsteal_file":           50,
+		// 旧内核按zone拆分
+		"pgscan_direct_normal": 3,
+		"pgsteal_kswapd_dma32": 2,
+		"pgfault":              1000,
+	}
+
+	var stats MemoryStats
+	addReclaimCounters(&stats, vmstat)
+
+	if stats.PagesScanned != 148 {
+		t.Errorf("PagesScanned = %d, want 148", stats.PagesScanned)
+	}
+	if stats.DirectReclaims != 43 {
+		t.Errorf("DirectReclaims = %d, want 43", stats.DirectReclaims)
+	}
+	if stats.PagesReclaimed != 112 {
+		t.Errorf("PagesReclaimed = %d, want 112", stats.PagesReclaimed)
+	}
+}
+
+func TestPSILevel(t *testing.T) {
+	tests := []struct {
+		some, full PressureStall
+		want       string
+	}{
+		{PressureStall{}, PressureStall{}, PressureLevelNormal},
+		{PressureStall{Avg10: 4.9}, PressureStall{Avg10: 0.9}, PressureLevelNormal},
+		{PressureStall{Avg10: 5}, PressureStall{}, PressureLevelWarn},
+		{PressureStall{}, PressureStall{Avg60: 1}, PressureLevelWarn},
+		{PressureStall{Avg60: 20}, PressureStall{}, PressureLevelUrgent},
+		{PressureStall{Avg10: 6}, PressureStall{Avg10: 5}, PressureLevelUrgent},
+		{PressureStall{Avg10: 50}, PressureStall{}, PressureLevelCritical},
+		{PressureStall{}, PressureStall{Avg10: 15}, PressureLevelCritical},
+		// avg300不参与判定
+		{PressureStall{Avg300: 90}, PressureStall{Avg300: 90}, PressureLevelNormal},
+	}
+
+	for _, test := range tests {
+		full := test.full
+		if got := psiLevel(&PSIInfo{Some: test.some, Full: &full}); got != test.want {
+			t.Errorf("some=%+v full=%+v: got %s, want %s", test.some, test.full, got, test.want)
+		}
+	}
+
+	// 没有full行时只按some判定
+	if got := psiLevel(&PSIInfo{Some: PressureStall{Avg10: 5}}); got != PressureLevelWarn {
+		t.Errorf("without full: got %s, want %s", got, PressureLevelWarn)
+	}
+}
@@ -4,6 +4,7 @@ package memory
 
 import (
 	"fmt"
+	"io"
 )
 
 // getPlatformMemoryInfo 获取平台内存信息
@@ -46,7 +47,32 @@ func getWindowsVirtualMemoryInfo(info *VirtualMemoryInfo) error {
 	return fmt.Errorf("Windows virtual memory info not implemented yet")
 }
 
-// getDarwinMemoryPressure Windows平台不支持Darwin内存压力
-func getDarwinMemoryPressure(pressure *MemoryPressure) error {
-	return fmt.Errorf("Darwin memory pressure not available on Windows")
+// getPlatformMemoryPressure Windows平台暂不支持内存压力
+func getPlatformMemoryPressure(pressure *MemoryPressure) error {
+	return fmt.Errorf("memory pressure monitoring not supported on Windows")
+}
+
+// getPlatformOOMStats Windows不支持OOM计数器
+func getPlatformOOMStats(stats *OOMStats) error {
+	return fmt.Errorf("OOM kill detection not supported on Windows")
+}
+
+// openPlatformKmsg Windows没有/dev/kmsg
+func openPlatformKmsg(callback func(event OOMEvent)) (io.Closer, error) {
+	return nil, fmt.Errorf("kernel log not supported on Windows")
+}
+
+// getPlatformNUMANodes Windows不支持NUMA节点统计
+func getPlatformNUMANodes() ([]NUMANode, error) {
+	return nil, fmt.Errorf("NUMA node statistics not supported on Windows")
+}
+
+// getPlatformKernelMemoryInfo Windows不支持内核内存特性统计
+func getPlatformKernelMemoryInfo(info *KernelMemoryInfo) error {
+	return fmt.Errorf("kernel memory statistics not supported on Windows")
+}
+
+// getPlatformZramDevices Windows没有zram
+func getPlatformZramDevices() ([]ZramDevice, error) {
+	return nil, fmt.Errorf("zram not supported on Windows")
 }
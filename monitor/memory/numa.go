@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// NUMANode NUMA节点内存信息
+type NUMANode struct {
+	ID          int     `json:"id"`           // 节点编号
+	CPUs        []int   `json:"cpus"`         // 节点上的逻辑CPU
+	Distances   []int   `json:"distances"`    // 到各节点的访问距离 (本节点为10)
+	Total       uint64  `json:"total"`        // 节点总内存 (bytes)
+	Free        uint64  `json:"free"`         // 节点空闲内存 (bytes)
+	Used        uint64  `json:"used"`         // 节点已用内存 (bytes)
+	UsedPercent float64 `json:"used_percent"` // 使用率百分比
+	FilePages   uint64  `json:"file_pages"`   // 页缓存 (bytes)
+	AnonPages   uint64  `json:"anon_pages"`   // 匿名页 (bytes)
+
+	// numastat累计计数器 (页)
+	NumaHit       uint64 `json:"numa_hit"`       // 按预期在本节点分配成功
+	NumaMiss      uint64 `json:"numa_miss"`      // 期望其他节点但因内存不足在本节点分配
+	NumaForeign   uint64 `json:"numa_foreign"`   // 期望本节点但实际在其他节点分配
+	InterleaveHit uint64 `json:"interleave_hit"` // 交错分配策略下按预期在本节点分配
+	LocalNode     uint64 `json:"local_node"`     // 在本节点运行的进程在本节点分配
+	OtherNode     uint64 `json:"other_node"`     // 在其他节点运行的进程在本节点分配（跨节点访问）
+
+	// 速率 (页/秒)，需两次采样
+	HitRate     float64 `json:"hit_rate"`     // numa_hit速率
+	MissRate    float64 `json:"miss_rate"`    // numa_miss速率
+	ForeignRate float64 `json:"foreign_rate"` // numa_foreign速率
+	LocalRate   float64 `json:"local_rate"`   // local_node速率
+	OtherRate   float64 `json:"other_rate"`   // other_node速率
+
+	// RemotePercent 本节点上为远端进程分配的页占比: other_node / (local_node + other_node)
+	// 有上一次采样时按区间增量计算，否则按开机以来的累计值计算
+	RemotePercent float64 `json:"remote_percent"`
+
+	LastUpdated time.Time `json:"last_updated"` // 最后更新时间
+}
+
+var lastNUMANodes map[int]NUMANode
+
+// GetNUMANodes 获取各NUMA节点的内存使用与跨节点分配统计
+// 速率基于与上一次调用之间的计数器差值，首次调用时为0
+func GetNUMANodes() ([]NUMANode, error) {
+	nodes, err := getPlatformNUMANodes()
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[int]NUMANode, len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Total >= node.Free {
+			node.Used = node.Total - node.Free
+		}
+		if node.Total > 0 {
+			node.UsedPercent = float64(node.Used) / float64(node.Total) * 100
+		}
+
+		local, other := node.LocalNode, node.OtherNode
+		if last, ok := lastNUMANodes[node.ID]; ok {
+			calculateNUMARates(&last, node)
+			local = procfs.CounterDelta(last.LocalNode, node.LocalNode)
+			other = procfs.CounterDelta(last.OtherNode, node.OtherNode)
+		}
+		if local+other > 0 {
+			node.RemotePercent = float64(other) / float64(local+other) * 100
+		}
+
+		current[node.ID] = *node
+	}
+	lastNUMANodes = current
+
+	return nodes, nil
+}
+
+// GetNUMANodesWithDuration 获取NUMA节点信息，首次调用时等待duration采样以得到速率
+func GetNUMANodesWithDuration(duration time.Duration) ([]NUMANode, error) {
+	if lastNUMANodes == nil {
+		if _, err := GetNUMANodes(); err != nil {
+			return nil, err
+		}
+		time.Sleep(duration)
+	}
+
+	return GetNUMANodes()
+}
+
+// calculateNUMARates 根据两次采样计算numastat速率 (页/秒)
+func calculateNUMARates(last, current *NUMANode) {
+	elapsed := current.LastUpdated.Sub(last.LastUpdated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	current.HitRate = float64(procfs.CounterDelta(last.NumaHit, current.NumaHit)) / elapsed
+	current.MissRate = float64(procfs.CounterDelta(last.NumaMiss, current.NumaMiss)) / elapsed
+	current.ForeignRate = float64(procfs.CounterDelta(last.NumaForeign, current.NumaForeign)) / elapsed
+	current.LocalRate = float64(procfs.CounterDelta(last.LocalNode, current.LocalNode)) / elapsed
+	current.OtherRate = float64(procfs.CounterDelta(last.OtherNode, current.OtherNode)) / elapsed
+}
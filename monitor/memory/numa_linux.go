@@ -0,0 +1,106 @@
+//go:build linux
+
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// sysNodePath NUMA节点sysfs目录
+const sysNodePath = "/sys/devices/system/node"
+
+// getPlatformNUMANodes 从/sys/devices/system/node/node*读取NUMA节点信息
+// 未启用NUMA的内核没有该目录；单路机器上只有node0
+func getPlatformNUMANodes() ([]NUMANode, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysNodePath, "node[0-9]*"))
+	if err != nil || len(dirs) == 0 {
+		return nil, fmt.Errorf("NUMA nodes not found in %s", sysNodePath)
+	}
+
+	now := time.Now()
+	var nodes []NUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+
+		meminfo, err := readNodeMeminfo(filepath.Join(dir, "meminfo"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", filepath.Join(dir, "meminfo"), err)
+		}
+
+		node := NUMANode{
+			ID:          id,
+			Total:       meminfo["MemTotal"],
+			Free:        meminfo["MemFree"],
+			FilePages:   meminfo["FilePages"],
+			AnonPages:   meminfo["AnonPages"],
+			LastUpdated: now,
+		}
+
+		if numastat, err := procfs.ReadKeyValueFile(filepath.Join(dir, "numastat")); err == nil {
+			node.NumaHit = numastat["numa_hit"]
+			node.NumaMiss = numastat["numa_miss"]
+			node.NumaForeign = numastat["numa_foreign"]
+			node.InterleaveHit = numastat["interleave_hit"]
+			node.LocalNode = numastat["local_node"]
+			node.OtherNode = numastat["other_node"]
+		}
+
+		if data, err := os.ReadFile(filepath.Join(dir, "cpulist")); err == nil {
+			node.CPUs, _ = procfs.ParseCPUList(string(data))
+		}
+
+		if data, err := os.ReadFile(filepath.Join(dir, "distance")); err == nil {
+			for _, field := range strings.Fields(string(data)) {
+				if distance, err := strconv.Atoi(field); err == nil {
+					node.Distances = append(node.Distances, distance)
+				}
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	return nodes, nil
+}
+
+// readNodeMeminfo 解析节点meminfo，格式: "Node 0 MemTotal:  4816632 kB"，kB值转换为字节
+func readNodeMeminfo(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	meminfo := make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] != "Node" {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 4 && fields[4] == "kB" {
+			value *= 1024
+		}
+		meminfo[strings.TrimSuffix(fields[2], ":")] = value
+	}
+
+	return meminfo, nil
+}
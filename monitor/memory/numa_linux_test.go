@@ -0,0 +1,38 @@
+//go:build linux
+
+package memory
+
+import "testing"
+
+func TestReadNodeMeminfo(t *testing.T) {
+	path := writeFixture(t, t.TempDir(), "meminfo", `Node 1 MemTotal:       16384000 kB
+Node 1 MemFree:         8192000 kB
+Node 1 MemUsed:         8192000 kB
+Node 1 FilePages:       2048000 kB
+Node 1 HugePages_Total:     8
+Node 1 HugePages_Free:      2
+
+`)
+
+	meminfo, err := readNodeMeminfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]uint64{
+		"MemTotal":        16384000 * 1024,
+		"MemFree":         8192000 * 1024,
+		"MemUsed":         8192000 * 1024,
+		"FilePages":       2048000 * 1024,
+		"HugePages_Total": 8,
+		"HugePages_Free":  2,
+	}
+	if len(meminfo) != len(want) {
+		t.Errorf("got %d keys, want %d: %v", len(meminfo), len(want), meminfo)
+	}
+	for key, value := range want {
+		if meminfo[key] != value {
+			t.Errorf("%s = %d, want %d", key, meminfo[key], value)
+		}
+	}
+}
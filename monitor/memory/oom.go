@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// OOM事件来源
+const (
+	OOMSourceKmsg   = "kmsg"   // 内核日志 (/dev/kmsg)，包含被杀进程信息
+	OOMSourceCgroup = "cgroup" // 当前cgroup的memory.events (v1为memory.oom_control)
+	OOMSourceVmstat = "vmstat" // 系统级/proc/vmstat oom_kill计数器
+)
+
+// OOMStats OOM计数器快照
+type OOMStats struct {
+	SystemKills uint64    `json:"system_kills"` // 系统累计OOM kill次数 (/proc/vmstat oom_kill，4.13+)
+	CgroupOOMs  uint64    `json:"cgroup_ooms"`  // 当前cgroup触及上限进入OOM的次数 (仅v2)
+	CgroupKills uint64    `json:"cgroup_kills"` // 当前cgroup内OOM kill次数
+	Cgroup      string    `json:"cgroup"`       // 计数器所在的cgroup文件，不可用时为空
+	LastUpdated time.Time `json:"last_updated"` // 最后更新时间
+}
+
+// OOMEvent OOM事件
+type OOMEvent struct {
+	Source     string    `json:"source"`     // 事件来源: kmsg, cgroup, vmstat
+	Time       time.Time `json:"time"`       // 事件时间，kmsg为内核时间戳换算的时间，计数器来源为检测到的时间
+	PID        int       `json:"pid"`        // 被杀进程PID (仅kmsg)
+	Process    string    `json:"process"`    // 被杀进程名 (仅kmsg)
+	UID        int       `json:"uid"`        // 被杀进程UID (仅kmsg)
+	Cgroup     string    `json:"cgroup"`     // 被杀进程所在cgroup (kmsg) 或计数器所在cgroup文件
+	Constraint string    `json:"constraint"` // OOM约束类型，如CONSTRAINT_NONE、CONSTRAINT_MEMCG (仅kmsg)
+	TotalVM    uint64    `json:"total_vm"`   // 被杀进程虚拟内存 (bytes，仅kmsg)
+	AnonRSS    uint64    `json:"anon_rss"`   // 被杀进程匿名页常驻内存 (bytes，仅kmsg)
+	FileRSS    uint64    `json:"file_rss"`   // 被杀进程文件页常驻内存 (bytes，仅kmsg)
+	Kills      uint64    `json:"kills"`      // 本事件代表的OOM kill次数
+	OOMs       uint64    `json:"ooms"`       // cgroup进入OOM但未杀进程的次数 (如随后回收成功或禁用了OOM killer)
+}
+
+// OOMWatcher OOM事件监视器
+// 周期性比较OOM计数器，并可选地读取/dev/kmsg以获得被杀进程信息。
+// kmsg报告的事件会抵消同一时间段内计数器的增量；计数器增加但kmsg未报告时（如日志被限速），
+// 等待一个周期后以计数器事件补报。
+// Stop之后可再次Start，每次Start后需重新调用Events获取新的事件通道
+type OOMWatcher struct {
+	interval time.Duration
+	useKmsg  bool
+
+	mu         sync.Mutex // 保护以下监视状态
+	events     chan OOMEvent
+	stopChan   chan struct{}
+	done       chan struct{} // 监视循环退出时关闭
+	isWatching bool
+	kmsg       io.Closer
+
+	victimsMu sync.Mutex
+	victims   []OOMEvent
+
+	// 以下字段仅由监视循环访问，Start在上一次循环退出后才重置
+	last          *OOMStats
+	pendingKills  uint64 // 上一周期推迟、等待kmsg解释的系统kill增量
+	pendingCgroup uint64 // 上一周期推迟、等待kmsg解释的cgroup kill增量
+}
+
+// GetOOMStats 获取OOM计数器
+func GetOOMStats() (*OOMStats, error) {
+	stats := &OOMStats{
+		LastUpdated: time.Now(),
+	}
+
+	err := getPlatformOOMStats(stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// NewOOMWatcher 创建OOM监视器，useKmsg为true时尝试读取/dev/kmsg（需要root或CAP_SYSLOG）
+func NewOOMWatcher(interval time.Duration, useKmsg bool) *OOMWatcher {
+	return &OOMWatcher{
+		interval: interval,
+		useKmsg:  useKmsg,
+		events:   make(chan OOMEvent, 16),
+	}
+}
+
+// Start 开始监视，/dev/kmsg不可读时仅使用计数器
+func (w *OOMWatcher) Start() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isWatching {
+		return fmt.Errorf("OOM watcher is already running")
+	}
+
+	// 重新启动时等待上一次的监视循环退出，其事件通道已被关闭
+	if w.done != nil {
+		<-w.done
+		w.events = make(chan OOMEvent, 16)
+	}
+
+	last, err := GetOOMStats()
+	if err != nil {
+		return err
+	}
+	w.last = last
+	w.pendingKills, w.pendingCgroup = 0, 0
+
+	w.victimsMu.Lock()
+	w.victims = nil
+	w.victimsMu.Unlock()
+
+	w.kmsg = nil
+	if w.useKmsg {
+		if kmsg, err := openPlatformKmsg(w.addVictim); err == nil {
+			w.kmsg = kmsg
+		}
+	}
+
+	w.stopChan = make(chan struct{})
+	w.done = make(chan struct{})
+	w.isWatching = true
+	go w.watchLoop(w.stopChan, w.events, w.done, w.kmsg != nil)
+
+	return nil
+}
+
+// Stop 停止监视并关闭事件通道，返回时监视循环已退出；重复调用无效果
+func (w *OOMWatcher) Stop() {
+	w.mu.Lock()
+	if !w.isWatching {
+		w.mu.Unlock()
+		return
+	}
+
+	close(w.stopChan)
+	if w.kmsg != nil {
+		w.kmsg.Close()
+	}
+	w.isWatching = false
+	done := w.done
+	w.mu.Unlock()
+
+	<-done
+}
+
+// Events 返回本次监视的OOM事件通道
+func (w *OOMWatcher) Events() <-chan OOMEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.events
+}
+
+// KmsgEnabled 是否正在读取/dev/kmsg
+func (w *OOMWatcher) KmsgEnabled() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.isWatching && w.kmsg != nil
+}
+
+// addVictim 记录kmsg解析出的被杀进程，由下一个周期发送
+func (w *OOMWatcher) addVictim(event OOMEvent) {
+	w.victimsMu.Lock()
+	w.victims = append(w.victims, event)
+	w.victimsMu.Unlock()
+}
+
+// watchLoop 监视循环，通道由Start传入，避免与重新启动时的字段赋值竞争
+func (w *OOMWatcher) watchLoop(stopChan <-chan struct{}, events chan<- OOMEvent, done chan<- struct{}, useKmsg bool) {
+	defer close(done)
+	defer close(events)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, event := range w.check(useKmsg) {
+				select {
+				case events <- event:
+				case <-stopChan:
+					return
+				}
+			}
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// check 执行一次检测，返回新事件
+func (w *OOMWatcher) check(useKmsg bool) []OOMEvent {
+	w.victimsMu.Lock()
+	victims := w.victims
+	w.victims = nil
+	w.victimsMu.Unlock()
+
+	current, err := GetOOMStats()
+	if err != nil {
+		return victims
+	}
+
+	return w.account(current, victims, useKmsg)
+}
+
+// account 用kmsg报告的被杀进程抵消计数器增量，返回本周期应发送的事件
+// 计数器先于内核日志更新，启用kmsg时本周期新增且未被解释的kill增量推迟一个周期；
+// 推迟的增量在下一周期优先被kmsg抵消，仍未被解释的部分才以计数器事件补报
+func (w *OOMWatcher) account(current *OOMStats, victims []OOMEvent, useKmsg bool) []OOMEvent {
+	events := victims
+
+	newKills := procfs.CounterDelta(w.last.SystemKills, current.SystemKills)
+	newCgroup := procfs.CounterDelta(w.last.CgroupKills, current.CgroupKills)
+	// memory.events的oom包含以kill结束的OOM，这里只保留未杀进程的部分，kmsg不会报告它们，因此不推迟
+	newOOMs := subtractFloor(procfs.CounterDelta(w.last.CgroupOOMs, current.CgroupOOMs), newCgroup)
+	w.last = current
+
+	// kmsg报告的每个被杀进程同时抵消一次系统与cgroup计数器增量
+	explained := uint64(len(victims))
+	kills, freshKills := explainKills(w.pendingKills, newKills, explained)
+	cgroupKills, freshCgroup := explainKills(w.pendingCgroup, newCgroup, explained)
+
+	if useKmsg {
+		w.pendingKills, w.pendingCgroup = freshKills, freshCgroup
+	} else {
+		kills += freshKills
+		cgroupKills += freshCgroup
+		w.pendingKills, w.pendingCgroup = 0, 0
+	}
+
+	if cgroupKills > 0 || newOOMs > 0 {
+		events = append(events, OOMEvent{
+			Source: OOMSourceCgroup,
+			Time:   current.LastUpdated,
+			Cgroup: current.Cgroup,
+			Kills:  cgroupKills,
+			OOMs:   newOOMs,
+		})
+		// cgroup内的kill同时计入系统计数器
+		kills = subtractFloor(kills, cgroupKills)
+	}
+	if kills > 0 {
+		events = append(events, OOMEvent{
+			Source: OOMSourceVmstat,
+			Time:   current.LastUpdated,
+			Kills:  kills,
+		})
+	}
+
+	return events
+}
+
+// explainKills 用explained个kmsg被杀进程依次抵消上一周期推迟的增量carried与本周期增量fresh
+// 返回carried中仍未被解释的部分（应补报）与fresh中未被解释的部分
+func explainKills(carried, fresh, explained uint64) (uint64, uint64) {
+	used := min(carried, explained)
+	return carried - used, subtractFloor(fresh, explained-used)
+}
+
+// subtractFloor 返回a-b，不小于0
+func subtractFloor(a, b uint64) uint64 {
+	if a < b {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,200 @@
+//go:build linux
+
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/singbox/manager/monitor/cgroup"
+	"github.com/singbox/manager/monitor/internal/procfs"
+)
+
+// OOM相关路径
+const (
+	devKmsgPath    = "/dev/kmsg"
+	procUptimePath = "/proc/uptime"
+)
+
+// kmsgKilledRegexp 匹配OOM killer的被杀进程日志，如
+// "Out of memory: Killed process 1234 (sing-box) total-vm:1024kB, anon-rss:512kB, file-rss:0kB, ..."
+// "Memory cgroup out of memory: Killed process 1234 (sing-box) total-vm:..."
+var kmsgKilledRegexp = regexp.MustCompile(`Killed process (\d+) \((.*?)\)(?:,? total-vm:(\d+)kB, anon-rss:(\d+)kB, file-rss:(\d+)kB)?(?:.*UID:(\d+))?`)
+
+// getPlatformOOMStats 从/proc/vmstat与当前cgroup读取OOM计数器
+func getPlatformOOMStats(stats *OOMStats) error {
+	vmstat, err := readVmstat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", procVmstatPath, err)
+	}
+	systemKills, hasSystem := vmstat["oom_kill"]
+	stats.SystemKills = systemKills
+
+	hasCgroup := false
+	if path, v2 := cgroupMemoryEventsPath(); path != "" {
+		if events, err := procfs.ReadKeyValueFile(path); err == nil {
+			stats.Cgroup = path
+			stats.CgroupKills = events["oom_kill"]
+			if v2 {
+				stats.CgroupOOMs = events["oom"]
+			}
+			hasCgroup = true
+		}
+	}
+
+	if !hasSystem && !hasCgroup {
+		return fmt.Errorf("OOM kill counters not available (requires Linux 4.13+)")
+	}
+	return nil
+}
+
+// cgroupMemoryEventsPath 返回当前进程内存cgroup的OOM计数文件
+// v2为memory.events；v1为memory.oom_control，两者都是"key value"格式
+func cgroupMemoryEventsPath() (string, bool) {
+	dir, v2, err := cgroup.ControllerDir("memory")
+	if err != nil {
+		return "", false
+	}
+
+	path := filepath.Join(dir, "memory.oom_control")
+	if v2 {
+		path = filepath.Join(dir, "memory.events")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, v2
+}
+
+// openPlatformKmsg 从/dev/kmsg末尾开始读取新的内核日志，解析出的OOM被杀进程通过callback回调
+func openPlatformKmsg(callback func(event OOMEvent)) (io.Closer, error) {
+	file, err := os.Open(devKmsgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", devKmsgPath, err)
+	}
+
+	// 对/dev/kmsg而言SEEK_END表示跳过已有日志，只读取之后的新记录
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek %s: %v", devKmsgPath, err)
+	}
+
+	bootTime := readBootTime()
+
+	go func() {
+		reader := &kmsgOOMReader{bootTime: bootTime}
+		buf := make([]byte, 8192)
+		for {
+			// 每次read返回一条完整记录
+			n, err := file.Read(buf)
+			if err != nil {
+				// EPIPE表示未读记录已被环形缓冲区覆盖，继续读取即可
+				if errors.Is(err, syscall.EPIPE) {
+					continue
+				}
+				return
+			}
+			if event, ok := reader.parse(string(buf[:n])); ok {
+				callback(event)
+			}
+		}
+	}()
+
+	return file, nil
+}
+
+// kmsgOOMReader 将kmsg记录解析为OOM事件
+type kmsgOOMReader struct {
+	bootTime time.Time
+	summary  map[string]string // 最近一条"oom-kill:"摘要行的键值
+}
+
+// parse 解析一条kmsg记录，格式: "priority,sequence,timestamp_us,flags;message\n[续行]"
+// 4.19+内核在被杀进程日志之前输出摘要行:
+// "oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),...,task_memcg=/docker/abc,task=sing-box,pid=1234,uid=0"
+func (r *kmsgOOMReader) parse(record string) (OOMEvent, bool) {
+	header, message, ok := strings.Cut(record, ";")
+	if !ok {
+		return OOMEvent{}, false
+	}
+	message, _, _ = strings.Cut(message, "\n")
+
+	if summary, ok := strings.CutPrefix(message, "oom-kill:"); ok {
+		r.summary = make(map[string]string)
+		for _, pair := range strings.Split(summary, ",") {
+			if key, value, ok := strings.Cut(pair, "="); ok {
+				r.summary[key] = value
+			}
+		}
+		return OOMEvent{}, false
+	}
+
+	match := kmsgKilledRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return OOMEvent{}, false
+	}
+
+	event := OOMEvent{
+		Source:  OOMSourceKmsg,
+		Time:    time.Now(),
+		Process: match[2],
+		Kills:   1,
+	}
+	event.PID, _ = strconv.Atoi(match[1])
+	if value, err := strconv.ParseUint(match[3], 10, 64); err == nil {
+		event.TotalVM = value * 1024
+	}
+	if value, err := strconv.ParseUint(match[4], 10, 64); err == nil {
+		event.AnonRSS = value * 1024
+	}
+	if value, err := strconv.ParseUint(match[5], 10, 64); err == nil {
+		event.FileRSS = value * 1024
+	}
+	event.UID, _ = strconv.Atoi(match[6])
+
+	// 内核时间戳为开机以来的微秒数
+	if fields := strings.Split(header, ","); len(fields) >= 3 && !r.bootTime.IsZero() {
+		if usec, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			event.Time = r.bootTime.Add(time.Duration(usec) * time.Microsecond)
+		}
+	}
+
+	if r.summary != nil && r.summary["pid"] == match[1] {
+		event.Cgroup = r.summary["task_memcg"]
+		event.Constraint = r.summary["constraint"]
+		if uid, err := strconv.Atoi(r.summary["uid"]); err == nil {
+			event.UID = uid
+		}
+	}
+	r.summary = nil
+
+	return event, true
+}
+
+// readBootTime 根据/proc/uptime估算开机时间，用于换算kmsg时间戳
+// uptime包含系统休眠时间而kmsg时间戳不包含，休眠过的系统上换算结果会偏早
+func readBootTime() time.Time {
+	data, err := os.ReadFile(procUptimePath)
+	if err != nil {
+		return time.Time{}
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return time.Time{}
+	}
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Now().Add(-time.Duration(uptime * float64(time.Second)))
+}
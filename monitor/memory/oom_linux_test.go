@@ -0,0 +1,58 @@
+//go:build linux
+
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKmsgOOMReaderParse(t *testing.T) {
+	bootTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reader := &kmsgOOMReader{bootTime: bootTime}
+
+	records := []string{
+		"6,1201,5000000,-;sing-box invoked oom-killer: gfp_mask=0xcc0(GFP_KERNEL), order=0, oom_score_adj=0\n",
+		"6,1202,5000100,-;oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=/,mems_allowed=0,oom_memcg=/docker/abc,task_memcg=/docker/abc,task=sing-box,pid=1234,uid=1000\n",
+		"3,1203,5000200,-;Memory cgroup out of memory: Killed process 1234 (sing-box) total-vm:1024kB, anon-rss:512kB, file-rss:64kB, shmem-rss:0kB, UID:1000 pgtables:100kB oom_score_adj:0\n SUBSYSTEM=memory\n",
+		// 4.19之前的内核没有摘要行，被杀进程日志中没有UID
+		"3,1300,9000000,-;Out of memory: Killed process 42 (my worker), total-vm:2048kB, anon-rss:1024kB, file-rss:0kB\n",
+		// 摘要行的pid与被杀进程不一致时不关联
+		"6,1400,9500000,-;oom-kill:constraint=CONSTRAINT_NONE,task_memcg=/system.slice/a.service,task=a,pid=7,uid=0\n",
+		"3,1401,9500100,-;Out of memory: Killed process 8 (b) total-vm:4kB, anon-rss:4kB, file-rss:0kB, shmem-rss:0kB, UID:33\n",
+		"6,1500,9600000,-;eth0: link up\n",
+		"malformed record without separator",
+	}
+
+	want := []OOMEvent{
+		{
+			Source: OOMSourceKmsg, Time: bootTime.Add(5000200 * time.Microsecond), PID: 1234, Process: "sing-box",
+			UID: 1000, Cgroup: "/docker/abc", Constraint: "CONSTRAINT_MEMCG",
+			TotalVM: 1024 * 1024, AnonRSS: 512 * 1024, FileRSS: 64 * 1024, Kills: 1,
+		},
+		{
+			Source: OOMSourceKmsg, Time: bootTime.Add(9 * time.Second), PID: 42, Process: "my worker",
+			TotalVM: 2048 * 1024, AnonRSS: 1024 * 1024, Kills: 1,
+		},
+		{
+			Source: OOMSourceKmsg, Time: bootTime.Add(9500100 * time.Microsecond), PID: 8, Process: "b",
+			UID: 33, TotalVM: 4 * 1024, AnonRSS: 4 * 1024, Kills: 1,
+		},
+	}
+
+	var got []OOMEvent
+	for _, record := range records {
+		if event, ok := reader.parse(record); ok {
+			got = append(got, event)
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// oomCycle 一个检测周期: 计数器当前值、kmsg报告的被杀进程数与期望事件
+type oomCycle struct {
+	systemKills uint64
+	cgroupKills uint64
+	cgroupOOMs  uint64
+	victims     int
+	want        []string
+}
+
+// summarizeOOMEvents 将事件简化为"来源:kills/ooms"便于比较
+func summarizeOOMEvents(events []OOMEvent) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, fmt.Sprintf("%s:%d/%d", event.Source, event.Kills, event.OOMs))
+	}
+	return result
+}
+
+func TestOOMWatcherAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		useKmsg bool
+		cycles  []oomCycle
+	}{
+		{
+			name: "counters only",
+			cycles: []oomCycle{
+				{systemKills: 1, want: []string{"vmstat:1/0"}},
+				{systemKills: 2, cgroupKills: 1, cgroupOOMs: 1, want: []string{"cgroup:1/0"}},
+				{systemKills: 2, cgroupKills: 1, cgroupOOMs: 2, want: []string{"cgroup:0/1"}},
+			},
+		},
+		{
+			name:    "kmsg in same cycle",
+			useKmsg: true,
+			cycles: []oomCycle{
+				{systemKills: 1, victims: 1, want: []string{"kmsg:1/0"}},
+				{systemKills: 1},
+			},
+		},
+		{
+			name:    "kmsg one cycle late",
+			useKmsg: true,
+			cycles: []oomCycle{
+				{systemKills: 1},
+				{systemKills: 1, victims: 1, want: []string{"kmsg:1/0"}},
+				{systemKills: 1},
+			},
+		},
+		{
+			name:    "kmsg lost",
+			useKmsg: true,
+			cycles: []oomCycle{
+				{systemKills: 1, cgroupKills: 1, cgroupOOMs: 1},
+				{systemKills: 1, cgroupKills: 1, cgroupOOMs: 1, want: []string{"cgroup:1/0"}},
+				{systemKills: 1, cgroupKills: 1, cgroupOOMs: 1},
+			},
+		},
+		{
+			// A的计数器增加后，B的计数器增加与A的kmsg在同一周期到达，B不能被立即补报
+			name:    "overlapping kills",
+			useKmsg: true,
+			cycles: []oomCycle{
+				{systemKills: 1},
+				{systemKills: 2, victims: 1, want: []string{"kmsg:1/0"}},
+				{systemKills: 2, victims: 1, want: []string{"kmsg:1/0"}},
+				{systemKills: 2},
+			},
+		},
+		{
+			name:    "one of two kills unexplained",
+			useKmsg: true,
+			cycles: []oomCycle{
+				{systemKills: 2},
+				{systemKills: 2, victims: 1, want: []string{"kmsg:1/0", "vmstat:1/0"}},
+				{systemKills: 2},
+			},
+		},
+		{
+			name:    "oom without kill is not delayed",
+			useKmsg: true,
+			cycles: []oomCycle{
+				{cgroupOOMs: 1, want: []string{"cgroup:0/1"}},
+			},
+		},
+		{
+			name: "counter reset",
+			cycles: []oomCycle{
+				{systemKills: 3, want: []string{"vmstat:3/0"}},
+				{systemKills: 0},
+				{systemKills: 1, want: []string{"vmstat:1/0"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &OOMWatcher{last: &OOMStats{}}
+			for i, cycle := range test.cycles {
+				current := &OOMStats{
+					SystemKills: cycle.systemKills,
+					CgroupKills: cycle.cgroupKills,
+					CgroupOOMs:  cycle.cgroupOOMs,
+					LastUpdated: time.Now(),
+				}
+				var victims []OOMEvent
+				for j := 0; j < cycle.victims; j++ {
+					victims = append(victims, OOMEvent{Source: OOMSourceKmsg, Kills: 1})
+				}
+
+				got := summarizeOOMEvents(w.account(current, victims, test.useKmsg))
+				if !reflect.DeepEqual(got, cycle.want) {
+					t.Errorf("cycle %d: got %v, want %v", i, got, cycle.want)
+				}
+			}
+		})
+	}
+}
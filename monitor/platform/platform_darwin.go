@@ -5,6 +5,7 @@ package platform
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
 	"strconv"
@@ -40,6 +41,26 @@ func isPlatformContainer() (bool, error) {
 	return container, err
 }
 
+// getPlatformPressure macOS不支持PSI
+func getPlatformPressure(info *PressureInfo) error {
+	return fmt.Errorf("pressure stall information not supported on macOS")
+}
+
+// getPlatformCurrentCgroup macOS没有cgroup
+func getPlatformCurrentCgroup() (string, error) {
+	return "", fmt.Errorf("cgroups not supported on macOS")
+}
+
+// openPlatformPressureTrigger macOS不支持PSI触发器
+func openPlatformPressureTrigger(w *PressureWatcher) error {
+	return fmt.Errorf("pressure stall information not supported on macOS")
+}
+
+// pollPlatformPressureTrigger macOS不支持PSI触发器
+func pollPlatformPressureTrigger(w *PressureWatcher, timeout time.Duration) (bool, error) {
+	return false, fmt.Errorf("pressure stall information not supported on macOS")
+}
+
 // getDarwinPlatformInfo 获取macOS平台信息
 func getDarwinPlatformInfo(info *PlatformInfo) error {
 	// 获取内核版本
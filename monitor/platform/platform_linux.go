@@ -46,7 +46,7 @@ func setLinuxCapabilities(caps *Capabilities) {
 	// 基本设置
 	caps.CPUTemperature = true
 	caps.CPUFrequency = true
-	caps.MemoryPressure = false
+	caps.MemoryPressure = true // PSI，需要4.20+内核
 	caps.DiskHealth = true
 	caps.NetworkDetails = true
 	caps.ProcessDetails = true
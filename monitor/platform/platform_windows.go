@@ -4,6 +4,7 @@ package platform
 
 import (
 	"fmt"
+	"time"
 )
 
 // getPlatformInfo 获取平台信息
@@ -31,6 +32,26 @@ func isPlatformContainer() (bool, error) {
 	return false, fmt.Errorf("Windows container detection not implemented yet")
 }
 
+// getPlatformPressure Windows不支持PSI
+func getPlatformPressure(info *PressureInfo) error {
+	return fmt.Errorf("pressure stall information not supported on Windows")
+}
+
+// getPlatformCurrentCgroup Windows没有cgroup
+func getPlatformCurrentCgroup() (string, error) {
+	return "", fmt.Errorf("cgroups not supported on Windows")
+}
+
+// openPlatformPressureTrigger Windows不支持PSI触发器
+func openPlatformPressureTrigger(w *PressureWatcher) error {
+	return fmt.Errorf("pressure stall information not supported on Windows")
+}
+
+// pollPlatformPressureTrigger Windows不支持PSI触发器
+func pollPlatformPressureTrigger(w *PressureWatcher, timeout time.Duration) (bool, error) {
+	return false, fmt.Errorf("pressure stall information not supported on Windows")
+}
+
 // getWindowsPlatformInfo 获取Windows平台信息 (占位符实现)
 func getWindowsPlatformInfo(info *PlatformInfo) error {
 	return fmt.Errorf("Windows platform info not implemented yet")
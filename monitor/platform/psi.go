@@ -0,0 +1,248 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// PSI资源类型
+const (
+	PressureResourceCPU    = "cpu"
+	PressureResourceMemory = "memory"
+	PressureResourceIO     = "io"
+)
+
+// PSI统计行类型
+const (
+	PressureSome = "some" // 至少一个任务停顿
+	PressureFull = "full" // 所有非空闲任务同时停顿
+)
+
+// PSI触发器窗口范围 (内核限制)
+const (
+	MinPressureWindow = 500 * time.Millisecond
+	MaxPressureWindow = 10 * time.Second
+)
+
+// PressureStall 一行PSI统计
+type PressureStall struct {
+	Avg10  float64 `json:"avg10"`  // 最近10秒停顿时间百分比
+	Avg60  float64 `json:"avg60"`  // 最近60秒停顿时间百分比
+	Avg300 float64 `json:"avg300"` // 最近300秒停顿时间百分比
+	Total  uint64  `json:"total"`  // 累计停顿时间 (微秒)
+}
+
+// PressureInfo 单个资源的压力停顿信息 (Pressure Stall Information)
+type PressureInfo struct {
+	Resource    string         `json:"resource"`       // 资源类型: cpu, memory, io
+	Cgroup      string         `json:"cgroup"`         // cgroup v2路径，系统级为空
+	Source      string         `json:"source"`         // 数据来源文件
+	Some        PressureStall  `json:"some"`           // 至少一个任务停顿的时间占比
+	Full        *PressureStall `json:"full,omitempty"` // 所有非空闲任务同时停顿的时间占比，5.13之前的内核cpu没有此行
+	LastUpdated time.Time      `json:"last_updated"`   // 最后更新时间
+}
+
+// PressureStats 系统级CPU、内存与IO压力
+type PressureStats struct {
+	CPU    *PressureInfo `json:"cpu"`    // CPU压力
+	Memory *PressureInfo `json:"memory"` // 内存压力
+	IO     *PressureInfo `json:"io"`     // IO压力
+}
+
+// PressureTrigger PSI触发器: 在Window时间窗口内停顿时间超过Threshold时触发
+type PressureTrigger struct {
+	Resource  string        `json:"resource"`  // 资源类型: cpu, memory, io
+	Cgroup    string        `json:"cgroup"`    // cgroup v2路径，为空表示系统级
+	Type      string        `json:"type"`      // some 或 full
+	Threshold time.Duration `json:"threshold"` // 窗口内累计停顿阈值 (微秒精度)
+	Window    time.Duration `json:"window"`    // 时间窗口，500ms~10s；非root用户需为2秒的整数倍
+}
+
+// PressureEvent PSI触发事件
+type PressureEvent struct {
+	Trigger  PressureTrigger `json:"trigger"`  // 触发的触发器
+	Source   string          `json:"source"`   // 触发器所在文件
+	Time     time.Time       `json:"time"`     // 触发时间
+	Pressure *PressureInfo   `json:"pressure"` // 触发时的压力快照，读取失败时为nil
+}
+
+// PressureWatcher 阻塞等待PSI触发器的监视器
+type PressureWatcher struct {
+	trigger PressureTrigger
+	source  string
+	file    *os.File
+	closed  atomic.Bool
+}
+
+// GetPressure 获取系统级资源压力 (/proc/pressure/<resource>)
+func GetPressure(resource string) (*PressureInfo, error) {
+	return GetCgroupPressure("", resource)
+}
+
+// GetCgroupPressure 获取指定cgroup v2的资源压力 (<cgroup>/<resource>.pressure)
+// cgroup为相对于cgroup v2挂载点的路径，如"/system.slice/docker.service"，为空时返回系统级压力
+func GetCgroupPressure(cgroup, resource string) (*PressureInfo, error) {
+	if err := validatePressureResource(resource); err != nil {
+		return nil, err
+	}
+
+	info := &PressureInfo{
+		Resource:    resource,
+		Cgroup:      cgroup,
+		LastUpdated: time.Now(),
+	}
+
+	err := getPlatformPressure(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// GetPressureStats 获取系统级CPU、内存与IO压力，不可用的资源为nil
+func GetPressureStats() (*PressureStats, error) {
+	return GetCgroupPressureStats("")
+}
+
+// GetCgroupPressureStats 获取指定cgroup v2的CPU、内存与IO压力，不可用的资源为nil
+func GetCgroupPressureStats(cgroup string) (*PressureStats, error) {
+	stats := &PressureStats{}
+
+	var lastErr error
+	for resource, target := range map[string]**PressureInfo{
+		PressureResourceCPU:    &stats.CPU,
+		PressureResourceMemory: &stats.Memory,
+		PressureResourceIO:     &stats.IO,
+	} {
+		info, err := GetCgroupPressure(cgroup, resource)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		*target = info
+	}
+
+	if stats.CPU == nil && stats.Memory == nil && stats.IO == nil {
+		return nil, lastErr
+	}
+
+	return stats, nil
+}
+
+// CurrentCgroup 返回当前进程所在的cgroup v2路径，可直接用于GetCgroupPressure
+func CurrentCgroup() (string, error) {
+	return getPlatformCurrentCgroup()
+}
+
+// NewPressureWatcher 注册PSI触发器并返回监视器，使用完毕后需调用Close
+// 内核对同一触发器每个时间窗口最多通知一次
+func NewPressureWatcher(trigger PressureTrigger) (*PressureWatcher, error) {
+	if err := validatePressureTrigger(trigger); err != nil {
+		return nil, err
+	}
+
+	watcher := &PressureWatcher{
+		trigger: trigger,
+	}
+
+	err := openPlatformPressureTrigger(watcher)
+	if err != nil {
+		return nil, err
+	}
+
+	return watcher, nil
+}
+
+// Wait 阻塞直到触发器触发或超时，超时返回nil事件；timeout<=0时一直等待
+func (w *PressureWatcher) Wait(timeout time.Duration) (*PressureEvent, error) {
+	if w.closed.Load() {
+		return nil, fmt.Errorf("pressure watcher closed")
+	}
+
+	fired, err := pollPlatformPressureTrigger(w, timeout)
+	if err != nil || !fired {
+		return nil, err
+	}
+
+	event := &PressureEvent{
+		Trigger: w.trigger,
+		Source:  w.source,
+		Time:    time.Now(),
+	}
+	event.Pressure, _ = GetCgroupPressure(w.trigger.Cgroup, w.trigger.Resource)
+
+	return event, nil
+}
+
+// Close 注销触发器，正在进行的Wait会返回错误
+func (w *PressureWatcher) Close() error {
+	if w.closed.Swap(true) {
+		return nil
+	}
+	return w.file.Close()
+}
+
+// WatchPressure 在后台监视PSI触发器，每次触发调用callback；关闭返回的通道即停止监视
+func WatchPressure(trigger PressureTrigger, callback func(event *PressureEvent)) (chan struct{}, error) {
+	watcher, err := NewPressureWatcher(trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	stopChan := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			event, err := watcher.Wait(0)
+			if err != nil {
+				return
+			}
+			if event != nil {
+				callback(event)
+			}
+		}
+	}()
+
+	go func() {
+		select {
+		case <-stopChan:
+		case <-done:
+		}
+		watcher.Close()
+	}()
+
+	return stopChan, nil
+}
+
+// validatePressureResource 检查资源类型
+func validatePressureResource(resource string) error {
+	switch resource {
+	case PressureResourceCPU, PressureResourceMemory, PressureResourceIO:
+		return nil
+	default:
+		return fmt.Errorf("unknown pressure resource: %q", resource)
+	}
+}
+
+// validatePressureTrigger 检查触发器参数是否满足内核要求
+func validatePressureTrigger(trigger PressureTrigger) error {
+	if err := validatePressureResource(trigger.Resource); err != nil {
+		return err
+	}
+	if trigger.Type != PressureSome && trigger.Type != PressureFull {
+		return fmt.Errorf("invalid pressure trigger type: %q", trigger.Type)
+	}
+	if trigger.Window < MinPressureWindow || trigger.Window > MaxPressureWindow {
+		return fmt.Errorf("pressure trigger window must be between %v and %v", MinPressureWindow, MaxPressureWindow)
+	}
+	if trigger.Threshold < time.Microsecond || trigger.Threshold > trigger.Window {
+		return fmt.Errorf("pressure trigger threshold must be between 1µs and the window")
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+//go:build linux
+
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/singbox/manager/monitor/cgroup"
+)
+
+// PSI相关路径
+const (
+	procPressurePath = "/proc/pressure"
+)
+
+// poll事件位
+const (
+	pollPri  = 0x2
+	pollErr  = 0x8
+	pollNval = 0x20
+)
+
+// pressurePollSlice 单次ppoll的最长阻塞时间，用于及时响应Close
+const pressurePollSlice = 200 * time.Millisecond
+
+// pollFd 对应内核struct pollfd
+type pollFd struct {
+	fd      int32
+	events  int16
+	revents int16
+}
+
+// getPlatformPressure 获取平台PSI信息
+func getPlatformPressure(info *PressureInfo) error {
+	source, err := pressureFilePath(info.Cgroup, info.Resource)
+	if err != nil {
+		return err
+	}
+	info.Source = source
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s (PSI requires Linux 4.20+ with CONFIG_PSI): %v", source, err)
+	}
+
+	return parsePressure(string(data), info)
+}
+
+// getPlatformCurrentCgroup 获取当前进程相对于cgroup v2挂载点的路径
+func getPlatformCurrentCgroup() (string, error) {
+	return cgroup.V2Path()
+}
+
+// openPlatformPressureTrigger 打开压力文件并写入触发器
+func openPlatformPressureTrigger(w *PressureWatcher) error {
+	source, err := pressureFilePath(w.trigger.Cgroup, w.trigger.Resource)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(source, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", source, err)
+	}
+
+	if _, err := file.Write([]byte(pressureTriggerString(w.trigger))); err != nil {
+		file.Close()
+		// 没有CAP_SYS_RESOURCE时内核(6.5+)要求窗口为2秒的整数倍
+		if errors.Is(err, syscall.EINVAL) && w.trigger.Window%(2*time.Second) != 0 {
+			return fmt.Errorf("failed to register PSI trigger on %s: %v (unprivileged triggers require a window that is a multiple of 2s)", source, err)
+		}
+		return fmt.Errorf("failed to register PSI trigger on %s: %v", source, err)
+	}
+
+	w.source = source
+	w.file = file
+	return nil
+}
+
+// pressureTriggerString 生成写入压力文件的触发器字符串
+// 格式: "<some|full> <threshold us> <window us>"，缓冲区最后一个字节会被内核替换为'\0'，因此需带上结尾的NUL
+func pressureTriggerString(trigger PressureTrigger) string {
+	return fmt.Sprintf("%s %d %d\x00", trigger.Type, trigger.Threshold.Microseconds(), trigger.Window.Microseconds())
+}
+
+// pollPlatformPressureTrigger 使用ppoll等待POLLPRI，返回是否触发
+// ppoll在RawConn.Control中执行，期间文件描述符不会被Close真正关闭
+func pollPlatformPressureTrigger(w *PressureWatcher, timeout time.Duration) (bool, error) {
+	rawConn, err := w.file.SyscallConn()
+	if err != nil {
+		return false, err
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	var fired bool
+	var pollErrno error
+	err = rawConn.Control(func(fd uintptr) {
+		fds := []pollFd{{fd: int32(fd), events: pollPri}}
+
+		for !w.closed.Load() {
+			slice := pressurePollSlice
+			if !deadline.IsZero() {
+				remaining := time.Until(deadline)
+				if remaining <= 0 {
+					return
+				}
+				if remaining < slice {
+					slice = remaining
+				}
+			}
+
+			ts := syscall.NsecToTimespec(slice.Nanoseconds())
+			n, _, errno := syscall.Syscall6(syscall.SYS_PPOLL, uintptr(unsafe.Pointer(&fds[0])),
+				uintptr(len(fds)), uintptr(unsafe.Pointer(&ts)), 0, 0, 0)
+			if errno == syscall.EINTR {
+				continue
+			}
+			if errno != 0 {
+				pollErrno = errno
+				return
+			}
+			if n == 0 {
+				continue
+			}
+
+			revents := fds[0].revents
+			if revents&(pollErr|pollNval) != 0 {
+				// 被监视的cgroup已删除
+				pollErrno = fmt.Errorf("PSI trigger on %s is no longer valid", w.source)
+				return
+			}
+			if revents&pollPri != 0 {
+				fired = true
+				return
+			}
+		}
+		pollErrno = fmt.Errorf("pressure watcher closed")
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return fired, pollErrno
+}
+
+// pressureFilePath 返回PSI文件路径，cgroup为空时为系统级文件
+func pressureFilePath(cgroupPath, resource string) (string, error) {
+	if cgroupPath == "" {
+		return filepath.Join(procPressurePath, resource), nil
+	}
+
+	mount, err := cgroup.V2Mount()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(mount, filepath.Clean("/"+cgroupPath), resource+".pressure"), nil
+}
+
+// parsePressure 解析PSI文件
+// 格式: "some avg10=0.00 avg60=0.00 avg300=0.00 total=0"
+func parsePressure(data string, info *PressureInfo) error {
+	found := false
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stall PressureStall
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "avg10":
+				stall.Avg10, _ = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stall.Avg60, _ = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stall.Avg300, _ = strconv.ParseFloat(value, 64)
+			case "total":
+				stall.Total, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+
+		switch fields[0] {
+		case PressureSome:
+			info.Some = stall
+			found = true
+		case PressureFull:
+			info.Full = &stall
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("no PSI data in %s", info.Source)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build linux
+
+package platform
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		some    PressureStall
+		full    *PressureStall
+		wantErr bool
+	}{
+		{
+			name: "memory",
+			data: "some avg10=1.25 avg60=0.50 avg300=0.10 total=123456\nfull avg10=0.75 avg60=0.25 avg300=0.05 total=65432\n",
+			some: PressureStall{Avg10: 1.25, Avg60: 0.5, Avg300: 0.1, Total: 123456},
+			full: &PressureStall{Avg10: 0.75, Avg60: 0.25, Avg300: 0.05, Total: 65432},
+		},
+		{
+			// 5.13之前的内核cpu.pressure没有full行
+			name: "missing full",
+			data: "some avg10=3.00 avg60=2.00 avg300=1.00 total=999\n",
+			some: PressureStall{Avg10: 3, Avg60: 2, Avg300: 1, Total: 999},
+		},
+		{
+			// 5.13+系统级cpu的full行恒为0
+			name: "zero full",
+			data: "some avg10=0.00 avg60=0.00 avg300=0.00 total=10\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			some: PressureStall{Total: 10},
+			full: &PressureStall{},
+		},
+		{name: "empty", data: "", wantErr: true},
+		{name: "full only", data: "full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n", wantErr: true},
+	}
+
+	for _, test := range tests {
+		info := &PressureInfo{Source: "/proc/pressure/test"}
+		err := parsePressure(test.data, info)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if info.Some != test.some {
+			t.Errorf("%s: some = %+v, want %+v", test.name, info.Some, test.some)
+		}
+		switch {
+		case test.full == nil && info.Full != nil:
+			t.Errorf("%s: full = %+v, want nil", test.name, *info.Full)
+		case test.full != nil && (info.Full == nil || *info.Full != *test.full):
+			t.Errorf("%s: full = %+v, want %+v", test.name, info.Full, *test.full)
+		}
+	}
+}
+
+func TestPressureTriggerString(t *testing.T) {
+	tests := []struct {
+		trigger PressureTrigger
+		want    string
+	}{
+		{PressureTrigger{Type: PressureSome, Threshold: 150 * time.Millisecond, Window: time.Second}, "some 150000 1000000\x00"},
+		{PressureTrigger{Type: PressureFull, Threshold: 100 * time.Millisecond, Window: 2 * time.Second}, "full 100000 2000000\x00"},
+		{PressureTrigger{Type: PressureSome, Threshold: 1500 * time.Microsecond, Window: MinPressureWindow}, "some 1500 500000\x00"},
+	}
+
+	for _, test := range tests {
+		if got := pressureTriggerString(test.trigger); got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.trigger, got, test.want)
+		}
+	}
+}